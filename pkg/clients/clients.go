@@ -31,7 +31,7 @@ import (
 
 // KubeClient holds instances of interfaces for making requests to kubernetes client.
 type KubeClient struct {
-	Kube *kubernetes.Clientset
+	Kube kubernetes.Interface
 }
 
 // Clients holds instances of interfaces for making requests to Tekton Pipelines.
@@ -57,6 +57,9 @@ type Clients struct {
 	// NOTE: ClusterTaskInterface (v1beta1) was removed in tektoncd/pipeline v1.9.x.
 	// ClusterTask resources are no longer supported upstream. Use Task instead.
 	ApprovalTask apclient.ApprovalTaskInterface
+
+	// approvalTasks scopes ApprovalTask to a namespace in NewClientSet.
+	approvalTasks apclient.OpenshiftpipelinesV1alpha1Interface
 }
 
 // NewClients instantiates the clientsets using the selected kubeconfig and cluster.
@@ -71,13 +74,6 @@ func NewClientsWithContext(configPath, clusterName, contextName, namespace strin
 
 // newClients contains the shared clientset construction for both public entry points.
 func newClients(configPath, clusterName, contextName, namespace string) (*Clients, error) {
-	var err error
-	scheme := createScheme()
-
-	clients := &Clients{
-		Scheme: scheme,
-	}
-
 	connection := "standard kubeconfig loading rules"
 	if configPath != "" {
 		connection = fmt.Sprintf("kubeconfig %q", configPath)
@@ -89,15 +85,38 @@ func newClients(configPath, clusterName, contextName, namespace string) (*Client
 		connection += fmt.Sprintf(", cluster %q", clusterName)
 	}
 
-	clients.KubeClient, clients.KubeConfig, err = newKubeClient(configPath, clusterName, contextName)
+	cfg, err := buildClientConfig(configPath, clusterName, contextName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubeclient using %s: %w", connection, err)
 	}
+	return newClientsForConfig(cfg, namespace, connection)
+}
+
+// NewClientsForConfig instantiates the clientsets against an already built REST
+// config, such as the one of a local envtest API server.
+func NewClientsForConfig(cfg *rest.Config, namespace string) (*Clients, error) {
+	return newClientsForConfig(rest.CopyConfig(cfg), namespace, fmt.Sprintf("API server %q", cfg.Host))
+}
+
+func newClientsForConfig(cfg *rest.Config, namespace, connection string) (*Clients, error) {
+	var err error
+	scheme := createScheme()
+
+	clients := &Clients{
+		Scheme:     scheme,
+		KubeConfig: cfg,
+	}
 
 	// We poll, so set our limits high.
 	clients.KubeConfig.QPS = 100
 	clients.KubeConfig.Burst = 200
 
+	kube, err := kubernetes.NewForConfig(clients.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubeclient using %s: %w", connection, err)
+	}
+	clients.KubeClient = &KubeClient{Kube: kube}
+
 	ctx := context.Background()
 	// ctx, cancel := context.WithCancel(ctx)
 	// defer cancel()
@@ -132,6 +151,29 @@ func newClients(configPath, clusterName, contextName, namespace string) (*Client
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pac clientset using %s: %w", connection, err)
 	}
+
+	clients.Route, err = routev1.NewForConfig(clients.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create route clients using %s: %w", connection, err)
+	}
+
+	configClient, err := configV1.NewForConfig(clients.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config clients using %s: %w", connection, err)
+	}
+	clients.ProxyConfig = configClient
+	clients.ClusterVersion = configClient.ClusterVersions()
+
+	consoleClient, err := consolev1.NewForConfig(clients.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create console clients using %s: %w", connection, err)
+	}
+	clients.ConsoleCLIDownload = consoleClient.ConsoleCLIDownloads()
+
+	clients.approvalTasks, err = apclient.NewForConfig(clients.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create approval task clients using %s: %w", connection, err)
+	}
 	clients.NewClientSet(namespace)
 	return clients, nil
 }
@@ -238,12 +280,7 @@ func (c *Clients) NewClientSet(namespace string) {
 	c.TaskClient = c.Tekton.TektonV1().Tasks(namespace)
 	c.TaskRunClient = c.Tekton.TektonV1().TaskRuns(namespace)
 	c.PipelineRunClient = c.Tekton.TektonV1().PipelineRuns(namespace)
-	c.Route = routev1.NewForConfigOrDie(c.KubeConfig)
-	c.ProxyConfig = configV1.NewForConfigOrDie(c.KubeConfig)
-	c.ClusterVersion = configV1.NewForConfigOrDie(c.KubeConfig).ClusterVersions()
-	c.ConsoleCLIDownload = consolev1.NewForConfigOrDie(c.KubeConfig).ConsoleCLIDownloads()
-	c.ApprovalTask = apclient.NewForConfigOrDie(c.KubeConfig).ApprovalTasks(namespace)
-	c.PacClientset = pacclientset.NewForConfigOrDie(c.KubeConfig)
+	c.ApprovalTask = c.approvalTasks.ApprovalTasks(namespace)
 }
 
 // NewClientFromKubeconfig creates a controller-runtime client from the provided kubeconfig.
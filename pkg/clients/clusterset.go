@@ -0,0 +1,246 @@
+package clients
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Role tells the hub of a multi-cluster environment apart from its spokes.
+type Role string
+
+const (
+	// RoleHub is the cluster the multi-cluster controllers run on.
+	RoleHub Role = "hub"
+	// RoleSpoke is a cluster workloads are dispatched to.
+	RoleSpoke Role = "spoke"
+)
+
+// ClusterConfig describes how to reach one cluster of a ClusterSet. It is plain
+// data so SynchronizedBeforeSuite can hand it from node 1 to every node.
+type ClusterConfig struct {
+	Name            string `json:"name"`
+	Role            Role   `json:"role"`
+	Kubeconfig      string `json:"kubeconfig"`
+	Cluster         string `json:"cluster,omitempty"`
+	Context         string `json:"context,omitempty"`
+	TargetNamespace string `json:"targetNamespace"`
+}
+
+// HubAndSpokes returns the configs of a hub and one spoke per kubeconfig, named
+// hub, spoke-1, spoke-2 and so on. spokeContexts is either empty, selecting the
+// current context of every spoke kubeconfig, or has one context per kubeconfig.
+func HubAndSpokes(hub ClusterConfig, spokeKubeconfigs, spokeContexts []string) ([]ClusterConfig, error) {
+	if len(spokeContexts) != 0 && len(spokeContexts) != len(spokeKubeconfigs) {
+		return nil, fmt.Errorf("got %d spoke contexts for %d spoke kubeconfigs, provide none or one per kubeconfig",
+			len(spokeContexts), len(spokeKubeconfigs))
+	}
+	hub.Name = cmp.Or(hub.Name, string(RoleHub))
+	hub.Role = RoleHub
+	configs := []ClusterConfig{hub}
+	for i, kubeconfig := range spokeKubeconfigs {
+		spoke := ClusterConfig{
+			Name:            fmt.Sprintf("%s-%d", RoleSpoke, i+1),
+			Role:            RoleSpoke,
+			Kubeconfig:      kubeconfig,
+			TargetNamespace: hub.TargetNamespace,
+		}
+		if len(spokeContexts) != 0 {
+			spoke.Context = spokeContexts[i]
+		}
+		configs = append(configs, spoke)
+	}
+	return configs, nil
+}
+
+// Cluster is one member of a ClusterSet with its clients.
+type Cluster struct {
+	ClusterConfig
+	*Clients
+}
+
+// NewControllerClient creates a controller-runtime client for the cluster, e.g.
+// for olm.ClusterBootstrap.
+func (c *Cluster) NewControllerClient() (client.Client, error) {
+	return c.NewClientFromKubeconfig(c.Kubeconfig, c.ClusterConfig.Cluster, c.Context)
+}
+
+// ClusterSet holds the clients of a hub and its spokes so specs can target a
+// cluster by name or role, or run the same assertion on every cluster.
+type ClusterSet struct {
+	clusters []*Cluster
+}
+
+// NewClusterSet builds the clients of every cluster. Names must be unique and
+// at most one cluster may be the hub.
+func NewClusterSet(configs ...ClusterConfig) (*ClusterSet, error) {
+	return newClusterSet(configs, func(cfg ClusterConfig) (*Clients, error) {
+		return NewClientsWithContext(cfg.Kubeconfig, cfg.Cluster, cfg.Context, cfg.TargetNamespace)
+	})
+}
+
+// NewClusterSetFromClients groups already built clients, such as FakeClients in
+// unit tests, into a ClusterSet.
+func NewClusterSetFromClients(clusters ...*Cluster) (*ClusterSet, error) {
+	if err := validateClusters(clusters); err != nil {
+		return nil, err
+	}
+	return &ClusterSet{clusters: clusters}, nil
+}
+
+func newClusterSet(configs []ClusterConfig, build func(ClusterConfig) (*Clients, error)) (*ClusterSet, error) {
+	clusters := make([]*Cluster, len(configs))
+	for i, cfg := range configs {
+		clusters[i] = &Cluster{ClusterConfig: cfg}
+	}
+	if err := validateClusters(clusters); err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		cs, err := build(cluster.ClusterConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clients for %s cluster %s: %w", cluster.Role, cluster.Name, err)
+		}
+		cluster.Clients = cs
+	}
+	return &ClusterSet{clusters: clusters}, nil
+}
+
+func validateClusters(clusters []*Cluster) error {
+	seen := map[string]bool{}
+	hubs := 0
+	for _, cluster := range clusters {
+		if cluster.Name == "" {
+			return errors.New("every cluster of a cluster set needs a name")
+		}
+		if seen[cluster.Name] {
+			return fmt.Errorf("cluster %s is defined more than once", cluster.Name)
+		}
+		seen[cluster.Name] = true
+		switch cluster.Role {
+		case RoleHub:
+			hubs++
+		case RoleSpoke:
+		default:
+			return fmt.Errorf("cluster %s has unknown role %q", cluster.Name, cluster.Role)
+		}
+	}
+	if hubs > 1 {
+		return fmt.Errorf("got %d hub clusters, want at most one", hubs)
+	}
+	return nil
+}
+
+// All returns every cluster, the hub first when it was configured first.
+func (s *ClusterSet) All() []*Cluster {
+	return slices.Clone(s.clusters)
+}
+
+// Configs returns the configs the set was built from.
+func (s *ClusterSet) Configs() []ClusterConfig {
+	configs := make([]ClusterConfig, len(s.clusters))
+	for i, cluster := range s.clusters {
+		configs[i] = cluster.ClusterConfig
+	}
+	return configs
+}
+
+// WithRole returns the clusters that have role.
+func (s *ClusterSet) WithRole(role Role) []*Cluster {
+	var clusters []*Cluster
+	for _, cluster := range s.clusters {
+		if cluster.Role == role {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// Hub returns the hub cluster, or nil when the set has none.
+func (s *ClusterSet) Hub() *Cluster {
+	if hubs := s.WithRole(RoleHub); len(hubs) != 0 {
+		return hubs[0]
+	}
+	return nil
+}
+
+// Spokes returns the spoke clusters in the order they were configured.
+func (s *ClusterSet) Spokes() []*Cluster {
+	return s.WithRole(RoleSpoke)
+}
+
+// Get returns the cluster called name.
+func (s *ClusterSet) Get(name string) (*Cluster, error) {
+	for _, cluster := range s.clusters {
+		if cluster.Name == name {
+			return cluster, nil
+		}
+	}
+	return nil, fmt.Errorf("cluster set has no cluster %s", name)
+}
+
+// ClusterResult is the outcome of a ForEach callback on one cluster.
+type ClusterResult struct {
+	Cluster string
+	Role    Role
+	Err     error
+}
+
+// Results are the per-cluster outcomes of ForEach, in cluster order.
+type Results []ClusterResult
+
+// Failed returns the results with an error.
+func (r Results) Failed() Results {
+	var failed Results
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of every failed cluster, each prefixed with the cluster
+// name, or returns nil when every cluster succeeded.
+func (r Results) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", result.Cluster, result.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// ForEach runs fn concurrently on every cluster with one of roles, or on every
+// cluster when no role is given, and waits for all of them. A failing cluster
+// does not stop the others, so the results show every cluster that is off.
+// fn should return errors rather than assert with Gomega; a panic in fn is
+// recovered and reported as that cluster's error.
+func (s *ClusterSet) ForEach(ctx context.Context, fn func(ctx context.Context, cluster *Cluster) error, roles ...Role) Results {
+	var targets []*Cluster
+	for _, cluster := range s.clusters {
+		if len(roles) == 0 || slices.Contains(roles, cluster.Role) {
+			targets = append(targets, cluster)
+		}
+	}
+
+	results := make(Results, len(targets))
+	var wg sync.WaitGroup
+	for i, cluster := range targets {
+		results[i] = ClusterResult{Cluster: cluster.Name, Role: cluster.Role}
+		wg.Go(func() {
+			defer func() {
+				if r := recover(); r != nil {
+					results[i].Err = fmt.Errorf("panic: %v", r)
+				}
+			}()
+			results[i].Err = fn(ctx, cluster)
+		})
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,133 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHubAndSpokes(t *testing.T) {
+	hub := ClusterConfig{Kubeconfig: "/tmp/hub", Context: "admin", TargetNamespace: "openshift-pipelines"}
+	got, err := HubAndSpokes(hub, []string{"/tmp/east", "/tmp/west"}, []string{"east", "west"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ClusterConfig{
+		{Name: "hub", Role: RoleHub, Kubeconfig: "/tmp/hub", Context: "admin", TargetNamespace: "openshift-pipelines"},
+		{Name: "spoke-1", Role: RoleSpoke, Kubeconfig: "/tmp/east", Context: "east", TargetNamespace: "openshift-pipelines"},
+		{Name: "spoke-2", Role: RoleSpoke, Kubeconfig: "/tmp/west", Context: "west", TargetNamespace: "openshift-pipelines"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("HubAndSpokes() = %+v, want %+v", got, want)
+	}
+
+	if _, err := HubAndSpokes(hub, []string{"/tmp/east", "/tmp/west"}, []string{"east"}); err == nil {
+		t.Fatal("HubAndSpokes() accepted one context for two spokes")
+	}
+}
+
+func TestNewClusterSetRejectsInvalidConfigs(t *testing.T) {
+	build := func(ClusterConfig) (*Clients, error) { return NewFakeClients().Clients, nil }
+	tests := []struct {
+		name    string
+		configs []ClusterConfig
+		wantErr string
+	}{{
+		name:    "duplicate name",
+		configs: []ClusterConfig{{Name: "hub", Role: RoleHub}, {Name: "hub", Role: RoleSpoke}},
+		wantErr: "more than once",
+	}, {
+		name:    "two hubs",
+		configs: []ClusterConfig{{Name: "a", Role: RoleHub}, {Name: "b", Role: RoleHub}},
+		wantErr: "at most one",
+	}, {
+		name:    "unknown role",
+		configs: []ClusterConfig{{Name: "a", Role: "edge"}},
+		wantErr: `unknown role "edge"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newClusterSet(tt.configs, build)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newClusterSet() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := newClusterSet([]ClusterConfig{{Name: "spoke-1", Role: RoleSpoke}}, func(ClusterConfig) (*Clients, error) {
+		return nil, errors.New("no such context")
+	})
+	if err == nil || !strings.Contains(err.Error(), "spoke cluster spoke-1: no such context") {
+		t.Fatalf("newClusterSet() error = %v, want the failing cluster named", err)
+	}
+}
+
+func TestClusterSetLookup(t *testing.T) {
+	set, err := NewClusterSetFromClients(
+		&Cluster{ClusterConfig: ClusterConfig{Name: "hub", Role: RoleHub}, Clients: NewFakeClients().Clients},
+		&Cluster{ClusterConfig: ClusterConfig{Name: "spoke-1", Role: RoleSpoke}, Clients: NewFakeClients().Clients},
+		&Cluster{ClusterConfig: ClusterConfig{Name: "spoke-2", Role: RoleSpoke}, Clients: NewFakeClients().Clients},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hub := set.Hub(); hub == nil || hub.Name != "hub" {
+		t.Fatalf("Hub() = %+v", hub)
+	}
+	var spokes []string
+	for _, spoke := range set.Spokes() {
+		spokes = append(spokes, spoke.Name)
+	}
+	if !reflect.DeepEqual(spokes, []string{"spoke-1", "spoke-2"}) {
+		t.Fatalf("Spokes() = %v", spokes)
+	}
+	if spoke, err := set.Get("spoke-2"); err != nil || spoke.Role != RoleSpoke {
+		t.Fatalf("Get(spoke-2) = %+v, %v", spoke, err)
+	}
+	if _, err := set.Get("spoke-3"); err == nil {
+		t.Fatal("Get(spoke-3) error = nil")
+	}
+}
+
+func TestClusterSetForEachReportsEveryCluster(t *testing.T) {
+	ctx := context.Background()
+	hub := NewFakeClients()
+	broken := NewFakeClients()
+	broken.InjectError("list", "namespaces", errors.New("unauthorized"))
+	set, err := NewClusterSetFromClients(
+		&Cluster{ClusterConfig: ClusterConfig{Name: "hub", Role: RoleHub}, Clients: hub.Clients},
+		&Cluster{ClusterConfig: ClusterConfig{Name: "spoke-1", Role: RoleSpoke}, Clients: broken.Clients},
+		&Cluster{ClusterConfig: ClusterConfig{Name: "spoke-2", Role: RoleSpoke}, Clients: NewFakeClients().Clients},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listNamespaces := func(ctx context.Context, cluster *Cluster) error {
+		_, err := cluster.KubeClient.Kube.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		return err
+	}
+	results := set.ForEach(ctx, listNamespaces)
+	if len(results) != 3 {
+		t.Fatalf("ForEach() returned %d results, want 3", len(results))
+	}
+	if failed := results.Failed(); len(failed) != 1 || failed[0].Cluster != "spoke-1" {
+		t.Fatalf("Failed() = %+v, want only spoke-1", failed)
+	}
+	if err := results.Err(); err == nil || err.Error() != "spoke-1: unauthorized" {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if results := set.ForEach(ctx, listNamespaces, RoleHub); len(results) != 1 || results.Err() != nil {
+		t.Fatalf("ForEach(RoleHub) = %+v, want only the healthy hub", results)
+	}
+
+	results = set.ForEach(ctx, func(context.Context, *Cluster) error { panic("boom") }, RoleSpoke)
+	if err := results.Err(); err == nil || !strings.Contains(err.Error(), "spoke-2: panic: boom") {
+		t.Fatalf("Err() = %v, want recovered panics", err)
+	}
+}
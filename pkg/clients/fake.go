@@ -0,0 +1,184 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	apfake "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	pacfake "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/fake"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	consolefake "github.com/openshift/client-go/console/clientset/versioned/fake"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	olmfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	pipelinefake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	triggersfake "github.com/tektoncd/triggers/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// FakeClients is a Clients backed by in-memory fake clientsets, for unit tests of
+// helpers that take *Clients. The fake clientsets are exposed so tests can seed
+// more objects, add reactors or inspect the recorded actions of a single one.
+//
+// Each fake keeps its own object tracker: an object created through Tekton is
+// not visible through Dynamic. KubeConfig is nil, so helpers that shell out to
+// oc or build their own REST clients cannot be exercised this way.
+type FakeClients struct {
+	*Clients
+
+	KubeFake         *kubefake.Clientset
+	DynamicFake      *dynamicfake.FakeDynamicClient
+	TektonFake       *pipelinefake.Clientset
+	TriggersFake     *triggersfake.Clientset
+	PacFake          *pacfake.Clientset
+	OperatorFake     *operatorfake.Clientset
+	OLMFake          *olmfake.Clientset
+	RouteFake        *routefake.Clientset
+	ConfigFake       *configfake.Clientset
+	ConsoleFake      *consolefake.Clientset
+	ApprovalTaskFake *apfake.Clientset
+}
+
+// fakeClientset collects the seed objects of one fake clientset by the scheme of
+// the types it serves, so NewFakeClients can hand every object to the right tracker.
+type fakeClientset struct {
+	scheme  *runtime.Scheme
+	objects []runtime.Object
+}
+
+func newFakeClientset(addToScheme func(*runtime.Scheme) error) *fakeClientset {
+	s := runtime.NewScheme()
+	utilruntime.Must(addToScheme(s))
+	return &fakeClientset{scheme: s}
+}
+
+// NewFakeClients returns Clients whose clientsets are fakes seeded with objects,
+// scoped to the default namespace; call NewClientSet to switch namespaces like
+// the suites do. Typed objects go to the fake clientset that serves their kind
+// and to DynamicFake; *unstructured.Unstructured objects only to DynamicFake.
+// It panics on an object of a kind none of the clientsets serve.
+func NewFakeClients(objects ...runtime.Object) *FakeClients {
+	kube := newFakeClientset(kubefake.AddToScheme)
+	tekton := newFakeClientset(pipelinefake.AddToScheme)
+	triggers := newFakeClientset(triggersfake.AddToScheme)
+	pac := newFakeClientset(pacfake.AddToScheme)
+	operator := newFakeClientset(operatorfake.AddToScheme)
+	olm := newFakeClientset(olmfake.AddToScheme)
+	route := newFakeClientset(routefake.AddToScheme)
+	config := newFakeClientset(configfake.AddToScheme)
+	console := newFakeClientset(consolefake.AddToScheme)
+	approvalTask := newFakeClientset(apfake.AddToScheme)
+	typed := []*fakeClientset{kube, tekton, triggers, pac, operator, olm, route, config, console, approvalTask}
+
+	dynamicScheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		kubefake.AddToScheme, pipelinefake.AddToScheme, triggersfake.AddToScheme, pacfake.AddToScheme,
+		operatorfake.AddToScheme, olmfake.AddToScheme, routefake.AddToScheme, configfake.AddToScheme,
+		consolefake.AddToScheme, apfake.AddToScheme,
+	} {
+		utilruntime.Must(addToScheme(dynamicScheme))
+	}
+
+	var dynamicObjects []runtime.Object
+	for _, obj := range objects {
+		dynamicObjects = append(dynamicObjects, obj)
+		if _, ok := obj.(*unstructured.Unstructured); ok {
+			continue
+		}
+		if !routeObject(typed, obj) {
+			panic(fmt.Sprintf("NewFakeClients: no fake clientset serves %T", obj))
+		}
+	}
+
+	f := &FakeClients{
+		KubeFake:         kubefake.NewSimpleClientset(kube.objects...),
+		DynamicFake:      dynamicfake.NewSimpleDynamicClient(dynamicScheme, dynamicObjects...),
+		TektonFake:       pipelinefake.NewSimpleClientset(tekton.objects...),
+		TriggersFake:     triggersfake.NewSimpleClientset(triggers.objects...),
+		PacFake:          pacfake.NewSimpleClientset(pac.objects...),
+		OperatorFake:     operatorfake.NewSimpleClientset(operator.objects...),
+		OLMFake:          olmfake.NewSimpleClientset(olm.objects...),
+		RouteFake:        routefake.NewSimpleClientset(route.objects...),
+		ConfigFake:       configfake.NewSimpleClientset(config.objects...),
+		ConsoleFake:      consolefake.NewSimpleClientset(console.objects...),
+		ApprovalTaskFake: apfake.NewSimpleClientset(approvalTask.objects...),
+	}
+	f.Clients = &Clients{
+		KubeClient:         &KubeClient{Kube: f.KubeFake},
+		Ctx:                context.Background(),
+		Dynamic:            f.DynamicFake,
+		Operator:           f.OperatorFake.OperatorV1alpha1(),
+		Scheme:             createScheme(),
+		OLM:                f.OLMFake,
+		Route:              f.RouteFake.RouteV1(),
+		ProxyConfig:        f.ConfigFake.ConfigV1(),
+		ClusterVersion:     f.ConfigFake.ConfigV1().ClusterVersions(),
+		ConsoleCLIDownload: f.ConsoleFake.ConsoleV1().ConsoleCLIDownloads(),
+		Tekton:             f.TektonFake,
+		PacClientset:       f.PacFake.PipelinesascodeV1alpha1(),
+		TriggersClient:     f.TriggersFake,
+		approvalTasks:      f.ApprovalTaskFake.OpenshiftpipelinesV1alpha1(),
+	}
+	f.NewClientSet(metav1.NamespaceDefault)
+	return f
+}
+
+// routeObject adds obj to the first clientset whose scheme knows its type.
+func routeObject(clientsets []*fakeClientset, obj runtime.Object) bool {
+	for _, cs := range clientsets {
+		if _, _, err := cs.scheme.ObjectKinds(obj); err == nil {
+			cs.objects = append(cs.objects, obj)
+			return true
+		}
+	}
+	return false
+}
+
+// fakes returns the reactor chains of every fake clientset.
+func (f *FakeClients) fakes() []*k8stesting.Fake {
+	return []*k8stesting.Fake{
+		&f.KubeFake.Fake, &f.DynamicFake.Fake, &f.TektonFake.Fake, &f.TriggersFake.Fake,
+		&f.PacFake.Fake, &f.OperatorFake.Fake, &f.OLMFake.Fake, &f.RouteFake.Fake,
+		&f.ConfigFake.Fake, &f.ConsoleFake.Fake, &f.ApprovalTaskFake.Fake,
+	}
+}
+
+// PrependReactor runs reaction before the object tracker of every fake clientset
+// for requests matching verb and resource, e.g. "get" and "pipelineruns"; "*"
+// matches any verb or resource. Resources are plural and lower case, so a
+// resource name is enough to target one clientset.
+func (f *FakeClients) PrependReactor(verb, resource string, reaction k8stesting.ReactionFunc) {
+	for _, fake := range f.fakes() {
+		fake.PrependReactor(verb, resource, reaction)
+	}
+}
+
+// InjectError makes every request matching verb and resource fail with err.
+func (f *FakeClients) InjectError(verb, resource string, err error) {
+	f.PrependReactor(verb, resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, err
+	})
+}
+
+// Actions returns the requests recorded by every fake clientset, grouped by
+// clientset in the order of the FakeClients fields.
+func (f *FakeClients) Actions() []k8stesting.Action {
+	var actions []k8stesting.Action
+	for _, fake := range f.fakes() {
+		actions = append(actions, fake.Actions()...)
+	}
+	return actions
+}
+
+// ClearActions forgets the requests recorded so far, e.g. after seeding objects.
+func (f *FakeClients) ClearActions() {
+	for _, fake := range f.fakes() {
+		fake.ClearActions()
+	}
+}
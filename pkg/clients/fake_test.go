@@ -0,0 +1,84 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewFakeClientsSeedsObjects(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "ci"}}
+	pr := &pipelinev1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ci"}}
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: "el", Namespace: "ci"}}
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1")
+	widget.SetKind("Widget")
+	widget.SetNamespace("ci")
+	widget.SetName("w")
+
+	c := NewFakeClients(secret, pr, route, widget)
+	c.NewClientSet("ci")
+
+	if _, err := c.KubeClient.Kube.CoreV1().Secrets("ci").Get(ctx, "token", metav1.GetOptions{}); err != nil {
+		t.Errorf("get secret: %v", err)
+	}
+	if _, err := c.PipelineRunClient.Get(ctx, "build", metav1.GetOptions{}); err != nil {
+		t.Errorf("get pipelinerun: %v", err)
+	}
+	if _, err := c.Route.Routes("ci").Get(ctx, "el", metav1.GetOptions{}); err != nil {
+		t.Errorf("get route: %v", err)
+	}
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	if _, err := c.Dynamic.Resource(gvr).Namespace("ci").Get(ctx, "w", metav1.GetOptions{}); err != nil {
+		t.Errorf("get widget: %v", err)
+	}
+	if _, err := c.TriggersClient.TriggersV1beta1().EventListeners("ci").Get(ctx, "build", metav1.GetOptions{}); err == nil {
+		t.Error("PipelineRun was also seeded into the Triggers fake")
+	}
+}
+
+func TestNewFakeClientsInjectError(t *testing.T) {
+	ctx := context.Background()
+	c := NewFakeClients(&pipelinev1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: metav1.NamespaceDefault}})
+	injected := errors.New("etcd is down")
+	c.InjectError("get", "pipelineruns", injected)
+
+	if _, err := c.PipelineRunClient.Get(ctx, "build", metav1.GetOptions{}); !errors.Is(err, injected) {
+		t.Fatalf("get pipelinerun error = %v, want %v", err, injected)
+	}
+	if _, err := c.PipelineRunClient.List(ctx, metav1.ListOptions{}); err != nil {
+		t.Fatalf("list pipelineruns error = %v, want only get to fail", err)
+	}
+	var verbs []string
+	for _, action := range c.Actions() {
+		verbs = append(verbs, action.GetVerb()+" "+action.GetResource().Resource)
+	}
+	if got := strings.Join(verbs, ","); got != "get pipelineruns,list pipelineruns" {
+		t.Errorf("Actions() = %s", got)
+	}
+}
+
+type unknownKind struct {
+	metav1.TypeMeta
+}
+
+func (u *unknownKind) DeepCopyObject() runtime.Object { return &unknownKind{TypeMeta: u.TypeMeta} }
+
+func TestNewFakeClientsPanicsOnUnknownKind(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "*clients.unknownKind") {
+			t.Fatalf("recover() = %v, want a panic naming the type", r)
+		}
+	}()
+	NewFakeClients(&unknownKind{})
+}
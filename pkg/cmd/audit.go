@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"gotest.tools/v3/icmd"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+// maxAuditOutputBytes caps the stdout/stderr kept per audit entry.
+const maxAuditOutputBytes = 2048
+
+// AuditEntry is one CLI invocation recorded while auditing is active.
+type AuditEntry struct {
+	Time     time.Time     `json:"time"`
+	Args     []string      `json:"args"`
+	Duration time.Duration `json:"durationNs"`
+	ExitCode int           `json:"exitCode"`
+	Timeout  bool          `json:"timeout,omitempty"`
+	Stdout   string        `json:"stdout,omitempty"`
+	Stderr   string        `json:"stderr,omitempty"`
+}
+
+var (
+	auditMu      sync.Mutex
+	auditEnabled bool
+	auditEntries []AuditEntry
+)
+
+// StartAudit begins a fresh audit log; every command run through this package is
+// recorded until StopAudit. hooks.AuditCommands calls it around each spec.
+func StartAudit() {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditEnabled = true
+	auditEntries = nil
+}
+
+// StopAudit ends auditing and returns the recorded commands, one JSON object per
+// line in execution order. It returns "" when nothing was recorded.
+func StopAudit() string {
+	auditMu.Lock()
+	entries := auditEntries
+	auditEnabled = false
+	auditEntries = nil
+	auditMu.Unlock()
+
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	for _, entry := range entries {
+		// AuditEntry holds only strings, numbers and times, which always encode.
+		_ = enc.Encode(entry)
+	}
+	return sb.String()
+}
+
+// audit records a finished command when auditing is active.
+func audit(c icmd.Cmd, res *Result, start time.Time) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if !auditEnabled {
+		return
+	}
+	auditEntries = append(auditEntries, AuditEntry{
+		Time:     start.UTC(),
+		Args:     redact.Args(c.Command),
+		Duration: time.Since(start),
+		ExitCode: res.ExitCode,
+		Timeout:  res.Timeout,
+		Stdout:   truncateOutput(res.Stdout()),
+		Stderr:   truncateOutput(res.Stderr()),
+	})
+}
+
+// truncateOutput keeps the redacted tail of output, bounded in both lines and bytes.
+func truncateOutput(output string) string {
+	output = redact.String(tail(output))
+	if len(output) <= maxAuditOutputBytes {
+		return output
+	}
+	return "... [truncated] " + output[len(output)-maxAuditOutputBytes:]
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/icmd"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+func TestAuditRecordsCommandsInOrder(t *testing.T) {
+	restore := SetExecutor(ExecutorFunc(func(c icmd.Cmd) *Result {
+		if c.Command[1] == "fail" {
+			return NewResult("", strings.Repeat("e", 3*maxAuditOutputBytes), 1, false)
+		}
+		return NewResult("ok", "", 0, false)
+	}))
+	defer restore()
+
+	Run("tkn", "before-audit")
+	StartAudit()
+	Run("tkn", "version")
+	Run("tkn", "fail")
+	Run("oc", "create", "secret", "generic", "s", "--from-literal=token=hunter2", "--password", "hunter2", "--token=hunter2")
+	log := StopAudit()
+	Run("tkn", "after-audit")
+
+	lines := strings.Split(strings.TrimSpace(log), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("audit log has %d lines, want 3:\n%s", len(lines), log)
+	}
+	entries := make([]AuditEntry, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+	}
+	if entries[0].Stdout != "ok" || entries[0].Time.IsZero() || strings.Join(entries[0].Args, " ") != "tkn version" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ExitCode != 1 || !strings.HasPrefix(entries[1].Stderr, "... [truncated] ") {
+		t.Fatalf("failed command was not truncated: exit %d, stderr %.40q", entries[1].ExitCode, entries[1].Stderr)
+	}
+	want := []string{"oc", "create", "secret", "generic", "s", "--from-literal=token=" + redact.Placeholder, "--password", redact.Placeholder, "--token=" + redact.Placeholder}
+	if !reflect.DeepEqual(entries[2].Args, want) {
+		t.Fatalf("args = %q, want %q", entries[2].Args, want)
+	}
+	if strings.Contains(log, "hunter2") {
+		t.Fatal("secret leaked into the audit log")
+	}
+	if StopAudit() != "" {
+		t.Fatal("commands were recorded after StopAudit")
+	}
+}
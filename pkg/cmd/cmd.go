@@ -2,7 +2,6 @@
 package cmd
 
 import (
-	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -11,13 +10,14 @@ import (
 	"gotest.tools/v3/icmd"
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
 
 	. "github.com/onsi/gomega" //nolint:revive,staticcheck // dot import is idiomatic for Gomega
 )
 
 // Run executes a command with the default CLI timeout.
-func Run(args ...string) *icmd.Result {
-	return icmd.RunCmd(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout})
+func Run(args ...string) *Result {
+	return execute(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout})
 }
 
 // Command adds configured Kubernetes connection flags to oc and kubectl commands.
@@ -55,57 +55,57 @@ func hasFlag(args []string, name string) bool {
 }
 
 // MustSucceed asserts that the command ran with exit code 0.
-func MustSucceed(args ...string) *icmd.Result {
+func MustSucceed(args ...string) *Result {
 	return Assert(icmd.Success, args...)
 }
 
 // Assert runs a command and verifies its exit code matches the expected one.
-func Assert(exp icmd.Expected, args ...string) *icmd.Result {
+func Assert(exp icmd.Expected, args ...string) *Result {
 	res := Run(args...)
 	Expect(res.ExitCode).To(Equal(exp.ExitCode),
-		fmt.Sprintf("expected exit code %d but got %d\nstdout:\n%s\nstderr:\n%s",
+		redact.Sprintf("expected exit code %d but got %d\nstdout:\n%s\nstderr:\n%s",
 			exp.ExitCode, res.ExitCode, res.Stdout(), res.Stderr()))
 	return res
 }
 
 // MustSucceedIncreasedTimeout asserts success using a custom timeout.
-func MustSucceedIncreasedTimeout(timeout time.Duration, args ...string) *icmd.Result {
+func MustSucceedIncreasedTimeout(timeout time.Duration, args ...string) *Result {
 	return AssertIncreasedTimeout(icmd.Success, timeout, args...)
 }
 
 // AssertIncreasedTimeout runs a command with a custom timeout and checks its exit code.
-func AssertIncreasedTimeout(exp icmd.Expected, timeout time.Duration, args ...string) *icmd.Result {
+func AssertIncreasedTimeout(exp icmd.Expected, timeout time.Duration, args ...string) *Result {
 	res := RunIncreasedTimeout(timeout, args...)
 	Expect(res.ExitCode).To(Equal(exp.ExitCode),
-		fmt.Sprintf("expected exit code %d but got %d\nstdout:\n%s\nstderr:\n%s",
+		redact.Sprintf("expected exit code %d but got %d\nstdout:\n%s\nstderr:\n%s",
 			exp.ExitCode, res.ExitCode, res.Stdout(), res.Stderr()))
 	return res
 }
 
 // RunIncreasedTimeout executes a command with the specified timeout.
-func RunIncreasedTimeout(timeout time.Duration, args ...string) *icmd.Result {
-	return icmd.RunCmd(icmd.Cmd{Command: Command(args...), Timeout: timeout})
+func RunIncreasedTimeout(timeout time.Duration, args ...string) *Result {
+	return execute(icmd.Cmd{Command: Command(args...), Timeout: timeout})
 }
 
 // RunWithEnv executes a command with additional environment variables appended to the current env.
-func RunWithEnv(env []string, args ...string) *icmd.Result {
-	return icmd.RunCmd(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout, Env: append(os.Environ(), env...)})
+func RunWithEnv(env []string, args ...string) *Result {
+	return execute(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout, Env: append(os.Environ(), env...)})
 }
 
 // MustSucceedWithEnv asserts exit code 0 for a command run with extra env vars.
-func MustSucceedWithEnv(env []string, args ...string) *icmd.Result {
+func MustSucceedWithEnv(env []string, args ...string) *Result {
 	res := RunWithEnv(env, args...)
 	Expect(res.ExitCode).To(Equal(0),
-		fmt.Sprintf("expected exit code 0 but got %d\nstdout:\n%s\nstderr:\n%s",
+		redact.Sprintf("expected exit code 0 but got %d\nstdout:\n%s\nstderr:\n%s",
 			res.ExitCode, res.Stdout(), res.Stderr()))
 	return res
 }
 
 // MustSucceedWithStdin runs a command with stdin piped from the given reader and asserts exit code 0.
-func MustSucceedWithStdin(stdin io.Reader, args ...string) *icmd.Result {
-	res := icmd.RunCmd(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout, Stdin: stdin})
+func MustSucceedWithStdin(stdin io.Reader, args ...string) *Result {
+	res := execute(icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout, Stdin: stdin})
 	Expect(res.ExitCode).To(Equal(0),
-		fmt.Sprintf("expected exit code 0 but got %d\nstdout:\n%s\nstderr:\n%s",
+		redact.Sprintf("expected exit code 0 but got %d\nstdout:\n%s\nstderr:\n%s",
 			res.ExitCode, res.Stdout(), res.Stderr()))
 	return res
 }
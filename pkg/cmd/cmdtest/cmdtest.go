@@ -0,0 +1,41 @@
+// Package cmdtest serves canned command results to unit tests of the packages
+// built on pkg/cmd, such as pkg/oc, pkg/opc and pkg/pipelines.
+package cmdtest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+)
+
+// Replay writes interactions to a fixture and replays it for the rest of the
+// test. The test fails if any interaction is left unserved.
+//
+// Usage:
+//
+//	cmdtest.Replay(t, cmd.Interaction{Args: []string{"oc", "get", "ns"}, Stdout: "demo\n"})
+func Replay(t testing.TB, interactions ...cmd.Interaction) *cmd.Replayer {
+	t.Helper()
+	data, err := json.Marshal(cmd.Fixture{Interactions: interactions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	replayer, restore, err := cmd.Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		restore()
+		if remaining := replayer.Remaining(); len(remaining) != 0 {
+			t.Errorf("unserved interactions: %+v", remaining)
+		}
+	})
+	return replayer
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gotest.tools/v3/icmd"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+
+	. "github.com/onsi/gomega" //nolint:revive,staticcheck // dot import is idiomatic for Gomega
+)
+
+// killGracePeriod bounds how long Wait may block on output pipes after the
+// process group has been killed.
+const killGracePeriod = 5 * time.Second
+
+// ContextExecutor is implemented by Executors that can abandon a command when its
+// context ends. Executors without it still work with the *Context helpers; they
+// just cannot interrupt a command that is already running.
+type ContextExecutor interface {
+	Executor
+	ExecuteContext(ctx context.Context, c icmd.Cmd) *Result
+}
+
+// ExecuteContext runs the command in its own process group and kills the whole
+// group once ctx is done or c.Timeout elapses, so no oc/opc children outlive an
+// interrupted spec. An interrupted command reports exit code -1, Timeout=true and
+// a note on stderr with how much of its time budget was consumed.
+func (IcmdExecutor) ExecuteContext(ctx context.Context, c icmd.Cmd) *Result {
+	if len(c.Command) == 0 {
+		return NewResult("", "no command given", 127, false)
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		return interrupted(ctx, NewResult("", "", 0, false), c.Command, start)
+	}
+
+	res := &Result{Args: c.Command}
+	execCmd := exec.Command(c.Command[0], c.Command[1:]...) //nolint:gosec // G204: subprocess args are controlled by test code
+	execCmd.Stdin = c.Stdin
+	execCmd.Dir = c.Dir
+	execCmd.Env = c.Env
+	execCmd.ExtraFiles = c.ExtraFiles
+	execCmd.Stdout = teeWriter(&res.stdout, c.Stdout)
+	execCmd.Stderr = teeWriter(&res.stderr, c.Stderr)
+	execCmd.WaitDelay = killGracePeriod
+	setProcessGroup(execCmd)
+
+	if err := execCmd.Start(); err != nil {
+		res.Error = err
+		res.ExitCode = 127
+		return res
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- execCmd.Wait() }()
+
+	select {
+	case err := <-done:
+		setExitError(res, err)
+		return res
+	case <-ctx.Done():
+		if err := killProcessGroup(execCmd); err != nil {
+			log.Printf("failed to kill process group of %q (pid=%d): %v", c.Command[0], execCmd.Process.Pid, err)
+		}
+		<-done
+		return interrupted(ctx, res, c.Command, start)
+	}
+}
+
+// RunContext executes a command bounded by both ctx and the default CLI timeout.
+// Pass a Ginkgo SpecContext so the command is killed when the spec is interrupted.
+func RunContext(ctx context.Context, args ...string) *Result {
+	return RunIncreasedTimeoutContext(ctx, config.CLITimeout, args...)
+}
+
+// RunIncreasedTimeoutContext executes a command bounded by both ctx and timeout.
+func RunIncreasedTimeoutContext(ctx context.Context, timeout time.Duration, args ...string) *Result {
+	return executeContext(ctx, icmd.Cmd{Command: Command(args...), Timeout: timeout})
+}
+
+// MustSucceedContext asserts that the command ran with exit code 0 before ctx ended.
+func MustSucceedContext(ctx context.Context, args ...string) *Result {
+	return assertResult(icmd.Success, RunContext(ctx, args...))
+}
+
+// MustSucceedIncreasedTimeoutContext asserts success using a custom timeout and ctx.
+func MustSucceedIncreasedTimeoutContext(ctx context.Context, timeout time.Duration, args ...string) *Result {
+	return assertResult(icmd.Success, RunIncreasedTimeoutContext(ctx, timeout, args...))
+}
+
+// AssertContext runs a command bounded by ctx and verifies its exit code.
+func AssertContext(ctx context.Context, exp icmd.Expected, args ...string) *Result {
+	return assertResult(exp, RunContext(ctx, args...))
+}
+
+// OutputContext is Output bounded by ctx.
+func OutputContext(ctx context.Context, args ...string) (string, error) {
+	res, duration := runTimed(func(c icmd.Cmd) *Result { return executeContext(ctx, c) },
+		icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout})
+	if err := resultError(args, res, duration); err != nil {
+		return "", err
+	}
+	return res.Stdout(), nil
+}
+
+// JSONContext is JSON bounded by ctx.
+func JSONContext[T any](ctx context.Context, args ...string) (T, error) {
+	return decode[T](func(c icmd.Cmd) *Result { return executeContext(ctx, c) },
+		withOutputFlag(args, "json"), jsonUnmarshal)
+}
+
+// executeContext hands a command to the active Executor, letting it observe ctx
+// when it implements ContextExecutor, and audits it.
+func executeContext(ctx context.Context, c icmd.Cmd) *Result {
+	start := time.Now()
+	var res *Result
+	e := CurrentExecutor()
+	if ce, ok := e.(ContextExecutor); ok {
+		res = ce.ExecuteContext(ctx, c)
+	} else if ctx.Err() != nil {
+		res = interrupted(ctx, NewResult("", "", 0, false), c.Command, start)
+	} else {
+		res = e.Execute(c)
+	}
+	audit(c, res, start)
+	return res
+}
+
+// assertResult fails the current spec unless res matches the expected exit code.
+func assertResult(exp icmd.Expected, res *Result) *Result {
+	ExpectWithOffset(2, res.ExitCode).To(Equal(exp.ExitCode),
+		redact.Sprintf("expected exit code %d but got %d\nstdout:\n%s\nstderr:\n%s",
+			exp.ExitCode, res.ExitCode, res.Stdout(), res.Stderr()))
+	return res
+}
+
+// interrupted marks res as cut short by ctx and records the consumed budget.
+func interrupted(ctx context.Context, res *Result, args []string, start time.Time) *Result {
+	cause := context.Cause(ctx)
+	note := fmt.Sprintf("[interrupted after %s: %v]", budgetUsage(ctx, start), cause)
+	_, _ = res.stderr.WriteString("\n" + note + "\n")
+	log.Printf("command %q %s", strings.Join(redact.Args(args), " "), note)
+	res.ExitCode = -1
+	res.Timeout = true
+	res.Error = cause
+	return res
+}
+
+// budgetUsage describes how much of ctx's time budget has elapsed since start.
+func budgetUsage(ctx context.Context, start time.Time) string {
+	elapsed := time.Since(start).Round(time.Millisecond)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return elapsed.String()
+	}
+	budget := deadline.Sub(start).Round(time.Millisecond)
+	if budget <= 0 {
+		return fmt.Sprintf("%s (budget already exhausted)", elapsed)
+	}
+	return fmt.Sprintf("%s of %s budget (%d%%)", elapsed, budget, int(100*elapsed/budget))
+}
+
+// teeWriter writes to buffer and, when set, also to the caller's writer.
+func teeWriter(buffer, extra io.Writer) io.Writer {
+	if extra == nil {
+		return buffer
+	}
+	return io.MultiWriter(buffer, extra)
+}
+
+// setExitError records the outcome of Wait the same way icmd does.
+func setExitError(res *Result, err error) {
+	if err == nil {
+		return
+	}
+	res.Error = err
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ProcessState != nil {
+		if code := exitErr.ProcessState.ExitCode(); code != -1 {
+			res.ExitCode = code
+			return
+		}
+	}
+	res.ExitCode = 127
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gotest.tools/v3/icmd"
+)
+
+func TestRunContextKillsProcessGroupOnCancel(t *testing.T) {
+	pidFile := t.TempDir() + "/child.pid"
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	res := RunContext(ctx, "sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("cancelled command returned after %s", elapsed)
+	}
+	if res.ExitCode != -1 || !res.Timeout || !errors.Is(res.Error, context.Canceled) {
+		t.Fatalf("exit code/timeout/error = %d/%t/%v", res.ExitCode, res.Timeout, res.Error)
+	}
+	if !strings.Contains(res.Stderr(), "[interrupted after") {
+		t.Fatalf("stderr has no interruption note: %q", res.Stderr())
+	}
+	// A killed child may linger as a zombie until init reaps it; only a running one is a leak.
+	alive := `sleep 0.2; pid=$(cat ` + pidFile + `); kill -0 $pid 2>/dev/null && ! grep -q '^State:.*Z' /proc/$pid/status 2>/dev/null`
+	if res := Run("sh", "-c", alive); res.ExitCode == 0 {
+		t.Fatal("background child survived cancellation")
+	}
+}
+
+func TestRunIncreasedTimeoutContextReportsBudget(t *testing.T) {
+	res := RunIncreasedTimeoutContext(context.Background(), 300*time.Millisecond, "sleep", "30")
+	if res.ExitCode != -1 || !res.Timeout || !errors.Is(res.Error, context.DeadlineExceeded) {
+		t.Fatalf("exit code/timeout/error = %d/%t/%v", res.ExitCode, res.Timeout, res.Error)
+	}
+	if !strings.Contains(res.Stderr(), "of 300ms budget (") {
+		t.Fatalf("stderr does not report the consumed budget: %q", res.Stderr())
+	}
+
+	_, err := OutputContext(context.Background(), "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteContextFallsBackForPlainExecutor(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	calls := 0
+	restore := SetExecutor(ExecutorFunc(func(icmd.Cmd) *Result {
+		calls++
+		return NewResult(`{"name":"served"}`, "", 0, false)
+	}))
+	defer restore()
+
+	obj, err := JSONContext[map[string]string](context.Background(), "tkn", "version")
+	if err != nil || obj["name"] != "served" {
+		t.Fatalf("JSONContext() = %v, %v", obj, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := RunContext(ctx, "tkn", "version")
+	if res.ExitCode != -1 || calls != 1 {
+		t.Fatalf("cancelled context ran the command: exit code %d, calls %d", res.ExitCode, calls)
+	}
+	var cmdErr *Error
+	if _, err := OutputContext(ctx, "tkn", "version"); !errors.As(err, &cmdErr) || !cmdErr.Timeout {
+		t.Fatalf("OutputContext() error = %v, want a timed-out *cmd.Error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"gotest.tools/v3/icmd"
+)
+
+// Executor runs a fully resolved command and returns its result. Every helper in
+// this package goes through the active Executor, so swapping it changes how all
+// oc, opc and tkn wrappers built on top of pkg/cmd reach the outside world.
+type Executor interface {
+	Execute(c icmd.Cmd) *Result
+}
+
+// ExecutorFunc adapts an ordinary function to the Executor interface.
+type ExecutorFunc func(c icmd.Cmd) *Result
+
+// Execute calls f(c).
+func (f ExecutorFunc) Execute(c icmd.Cmd) *Result {
+	return f(c)
+}
+
+// IcmdExecutor is the default Executor; it runs commands as local processes through icmd.
+type IcmdExecutor struct{}
+
+// Execute runs the command with icmd.RunCmd.
+func (IcmdExecutor) Execute(c icmd.Cmd) *Result {
+	return fromIcmd(c.Command, icmd.RunCmd(c))
+}
+
+var (
+	executorMu sync.RWMutex
+	executor   Executor = IcmdExecutor{}
+)
+
+// SetExecutor replaces the active Executor and returns a function restoring the
+// previous one. Passing nil restores the default icmd backend.
+//
+// Usage:
+//
+//	restore := cmd.SetExecutor(replayer)
+//	defer restore()
+func SetExecutor(e Executor) (restore func()) {
+	if e == nil {
+		e = IcmdExecutor{}
+	}
+	executorMu.Lock()
+	previous := executor
+	executor = e
+	executorMu.Unlock()
+	return func() {
+		executorMu.Lock()
+		executor = previous
+		executorMu.Unlock()
+	}
+}
+
+// CurrentExecutor returns the active Executor.
+func CurrentExecutor() Executor {
+	executorMu.RLock()
+	defer executorMu.RUnlock()
+	return executor
+}
+
+// execute hands a command to the active Executor and audits it.
+func execute(c icmd.Cmd) *Result {
+	start := time.Now()
+	res := CurrentExecutor().Execute(c)
+	audit(c, res, start)
+	return res
+}
+
+// exitError mirrors the error text exec reports for a non-zero exit status.
+type exitError int
+
+func (e exitError) Error() string {
+	return "exit status " + strconv.Itoa(int(e))
+}
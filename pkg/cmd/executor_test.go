@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"gotest.tools/v3/icmd"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+func TestSetExecutorRoutesHelpers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	var got []string
+	restore := SetExecutor(ExecutorFunc(func(c icmd.Cmd) *Result {
+		got = c.Command
+		return NewResult("served", "", 0, false)
+	}))
+	defer restore()
+
+	if out := MustSucceed("tkn", "version").Stdout(); out != "served" {
+		t.Fatalf("MustSucceed() stdout = %q, want output from the installed executor", out)
+	}
+	if strings.Join(got, " ") != "tkn version" {
+		t.Fatalf("executor received %q", got)
+	}
+}
+
+func TestNewResultReportsExitCode(t *testing.T) {
+	res := NewResult("out", "err", 3, false)
+	if res.Stdout() != "out" || res.Stderr() != "err" || res.Combined() != "outerr" {
+		t.Fatalf("unexpected output: stdout=%q stderr=%q", res.Stdout(), res.Stderr())
+	}
+	if res.ExitCode != 3 || res.Error == nil || res.Error.Error() != "exit status 3" {
+		t.Fatalf("exit code/error = %d/%v", res.ExitCode, res.Error)
+	}
+	if err := res.Compare(icmd.Expected{ExitCode: 3, Out: "out", Err: "err"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Compare(icmd.Expected{ExitCode: 0, Out: icmd.None}); err == nil || !strings.Contains(err.Error(), "ExitCode was 3 expected 0") || !strings.Contains(err.Error(), `Expected stdout to contain "[NOTHING]"`) {
+		t.Fatalf("Compare() error = %v, want the exit code and stdout mismatch", err)
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	original := *config.Flags
+	defer func() { *config.Flags = original }()
+	config.Flags.Kubeconfig = "/tmp/recorded"
+	config.Flags.Context = ""
+	config.Flags.Cluster = ""
+	addFakeOC(t)
+	fixture := filepath.Join(t.TempDir(), "fixtures", "oc.json")
+
+	stop := Record(fixture)
+	recorded := RunWithEnv([]string{"RECORDED_ENV=1"}, "oc", "get", "pods")
+	failed := Run("sh", "-c", "echo boom >&2; exit 4")
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if failed.ExitCode != 4 {
+		t.Fatalf("recorded exit code = %d", failed.ExitCode)
+	}
+
+	// Replay against a different kubeconfig: injected connection flags are not part of the match.
+	config.Flags.Kubeconfig = "/tmp/replayed"
+	replayer, restore, err := Replay(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restore()
+
+	replayed := RunWithEnv([]string{"RECORDED_ENV=1"}, "oc", "get", "pods")
+	if replayed.ExitCode != 0 || replayed.Stdout() != recorded.Stdout() {
+		t.Fatalf("replayed %d/%q, want 0/%q", replayed.ExitCode, replayed.Stdout(), recorded.Stdout())
+	}
+	replayedFailure := Run("sh", "-c", "echo boom >&2; exit 4")
+	if replayedFailure.ExitCode != 4 || replayedFailure.Stderr() != "boom\n" {
+		t.Fatalf("replayed failure %d/%q", replayedFailure.ExitCode, replayedFailure.Stderr())
+	}
+	if remaining := replayer.Remaining(); len(remaining) != 0 {
+		t.Fatalf("unserved interactions: %+v", remaining)
+	}
+
+	unmatched := Run("oc", "get", "pods")
+	if unmatched.ExitCode != 127 || !strings.Contains(unmatched.Stderr(), "no unused interaction") {
+		t.Fatalf("unmatched command returned %d/%q", unmatched.ExitCode, unmatched.Stderr())
+	}
+}
+
+func TestRecorderCapturesStdin(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fixture := filepath.Join(t.TempDir(), "stdin.json")
+
+	stop := Record(fixture)
+	res := MustSucceedWithStdin(strings.NewReader("piped"), "cat")
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if res.Stdout() != "piped" {
+		t.Fatalf("stdin was not forwarded to the command: %q", res.Stdout())
+	}
+
+	_, restore, err := Replay(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restore()
+	if out := MustSucceedWithStdin(strings.NewReader("piped"), "cat").Stdout(); out != "piped" {
+		t.Fatalf("replayed stdout = %q", out)
+	}
+	if res := Run("cat"); res.ExitCode != 127 {
+		t.Fatalf("command with different stdin matched a recorded interaction: %d", res.ExitCode)
+	}
+}
+
+func TestRecorderRedactsSecrets(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	const secret = "recorded-webhook-secret"
+	redact.Register(secret)
+	fixture := filepath.Join(t.TempDir(), "secret.json")
+	env := []string{"WEBHOOK_SECRET=" + secret}
+	script := `echo "$WEBHOOK_SECRET"; echo "$1" >&2`
+
+	stop := Record(fixture)
+	res := RunWithEnv(env, "sh", "-c", script, "--token", secret)
+	MustSucceedWithStdin(strings.NewReader(secret), "cat")
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if res.Stdout() != secret+"\n" {
+		t.Fatalf("the live result was redacted: %q", res.Stdout())
+	}
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) || !strings.Contains(string(data), redact.Placeholder) {
+		t.Fatalf("fixture holds the secret:\n%s", data)
+	}
+
+	_, restore, err := Replay(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restore()
+	if replayed := RunWithEnv(env, "sh", "-c", script, "--token", secret); replayed.ExitCode != 0 || replayed.Stdout() != redact.Placeholder+"\n" {
+		t.Fatalf("replayed %d/%q, want the redacted output", replayed.ExitCode, replayed.Stdout())
+	}
+	if out := MustSucceedWithStdin(strings.NewReader(secret), "cat").Stdout(); out != redact.Placeholder {
+		t.Fatalf("replayed stdout = %q, want the redacted output", out)
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gotest.tools/v3/icmd"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+
+	. "github.com/onsi/gomega" //nolint:revive,staticcheck // dot import is idiomatic for Gomega
+)
+
+// maxTailLines caps how much stderr/stdout an Error carries.
+const maxTailLines = 20
+
+// Error describes a CLI invocation that did not produce a usable result: it exited
+// non-zero, timed out, or its output could not be decoded.
+type Error struct {
+	Args     []string
+	ExitCode int
+	Timeout  bool
+	Duration time.Duration
+	// Stderr holds the last lines of standard error.
+	Stderr string
+	// Stdout holds the last lines of standard output; set only for decode failures.
+	Stdout string
+	// Err is the decode failure, if any.
+	Err error
+}
+
+// Error describes the failure with secrets redacted from the command line and output.
+func (e *Error) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: ", strings.Join(redact.Args(e.Args), " "))
+	switch {
+	case e.Timeout:
+		fmt.Fprintf(&sb, "timed out after %s", e.Duration.Round(time.Millisecond))
+	case e.Err != nil:
+		fmt.Fprintf(&sb, "failed to decode output after %s: %v", e.Duration.Round(time.Millisecond), e.Err)
+	default:
+		fmt.Fprintf(&sb, "exit code %d after %s", e.ExitCode, e.Duration.Round(time.Millisecond))
+	}
+	if e.Stderr != "" {
+		fmt.Fprintf(&sb, "\nstderr:\n%s", e.Stderr)
+	}
+	if e.Stdout != "" {
+		fmt.Fprintf(&sb, "\nstdout:\n%s", e.Stdout)
+	}
+	return redact.String(sb.String())
+}
+
+// Unwrap returns the decode failure, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StderrContains reports whether err is an *Error whose stderr tail contains substr.
+func StderrContains(err error, substr string) bool {
+	var cmdErr *Error
+	return errors.As(err, &cmdErr) && strings.Contains(cmdErr.Stderr, substr)
+}
+
+// Output runs a command with the default CLI timeout and returns its stdout.
+// A non-zero exit code or timeout is returned as an *Error.
+func Output(args ...string) (string, error) {
+	res, duration := runTimed(execute, icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout})
+	if err := resultError(args, res, duration); err != nil {
+		return "", err
+	}
+	return res.Stdout(), nil
+}
+
+// JSON runs a command with "-o json" appended (unless an output flag is already
+// present) and decodes stdout into a value of type T.
+//
+// Usage:
+//
+//	tr, err := cmd.JSON[v1.TaskRun]("opc", "taskrun", "describe", "--last", "-n", ns)
+func JSON[T any](args ...string) (T, error) {
+	return decode[T](execute, withOutputFlag(args, "json"), jsonUnmarshal)
+}
+
+// YAML runs a command with "-o yaml" appended (unless an output flag is already
+// present) and decodes stdout into a value of type T. Fields are matched by their
+// json tags, so Kubernetes API types decode as expected.
+func YAML[T any](args ...string) (T, error) {
+	return decode[T](execute, withOutputFlag(args, "yaml"), yamlUnmarshal)
+}
+
+// Unstructured runs a command with "-o json" and decodes a single object or list.
+func Unstructured(args ...string) (*unstructured.Unstructured, error) {
+	obj, err := JSON[map[string]any](args...)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// MustJSON is JSON that fails the current spec if the command or decoding fails.
+func MustJSON[T any](args ...string) T {
+	v, err := JSON[T](args...)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return v
+}
+
+// jsonUnmarshal and yamlUnmarshal are the decoders used by JSON and YAML.
+var (
+	jsonUnmarshal = json.Unmarshal
+	yamlUnmarshal = func(data []byte, v any) error { return yaml.Unmarshal(data, v) }
+)
+
+// decode runs args via run and unmarshals stdout, wrapping every failure in an *Error.
+func decode[T any](run func(icmd.Cmd) *Result, args []string, unmarshal func([]byte, any) error) (T, error) {
+	var v T
+	res, duration := runTimed(run, icmd.Cmd{Command: Command(args...), Timeout: config.CLITimeout})
+	if err := resultError(args, res, duration); err != nil {
+		return v, err
+	}
+	if err := unmarshal([]byte(res.Stdout()), &v); err != nil {
+		return v, &Error{
+			Args:     args,
+			ExitCode: res.ExitCode,
+			Duration: duration,
+			Stderr:   tail(res.Stderr()),
+			Stdout:   tail(res.Stdout()),
+			Err:      err,
+		}
+	}
+	return v, nil
+}
+
+// runTimed executes a command with run and measures it.
+func runTimed(run func(icmd.Cmd) *Result, c icmd.Cmd) (*Result, time.Duration) {
+	start := time.Now()
+	res := run(c)
+	return res, time.Since(start)
+}
+
+// resultError converts a failed or timed-out result into an *Error.
+func resultError(args []string, res *Result, duration time.Duration) error {
+	if res.ExitCode == 0 && !res.Timeout {
+		return nil
+	}
+	return &Error{
+		Args:     args,
+		ExitCode: res.ExitCode,
+		Timeout:  res.Timeout,
+		Duration: duration,
+		Stderr:   tail(res.Stderr()),
+	}
+}
+
+// withOutputFlag appends "-o format" unless args already select an output format.
+func withOutputFlag(args []string, format string) []string {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if isOutputFlag(arg) {
+			return args
+		}
+	}
+	return append(append([]string{}, args...), "-o", format)
+}
+
+// outputFormats are the formats kubectl-style CLIs accept for -o; the ones
+// ending in "=" take a template or column spec.
+var outputFormats = []string{"json", "yaml", "wide", "name", "jsonpath=", "jsonpath-file=", "go-template=", "go-template-file=", "custom-columns=", "custom-columns-file=", "template="}
+
+// isOutputFlag reports whether arg selects an output format: "-o" or "--output"
+// followed by a value, "--output=FMT", or "-o=FMT" and "-oFMT" for a known
+// format, so a flag such as -oauth-token is not taken for one.
+func isOutputFlag(arg string) bool {
+	switch {
+	case arg == "-o", arg == "--output", strings.HasPrefix(arg, "--output="):
+		return true
+	case strings.HasPrefix(arg, "-o="):
+		return isOutputFormat(arg[len("-o="):])
+	case strings.HasPrefix(arg, "-o"):
+		return isOutputFormat(arg[len("-o"):])
+	}
+	return false
+}
+
+// isOutputFormat reports whether value is one of outputFormats.
+func isOutputFormat(value string) bool {
+	for _, format := range outputFormats {
+		if value == format || strings.HasSuffix(format, "=") && strings.HasPrefix(value, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// tail keeps the last maxTailLines lines of output.
+func tail(output string) string {
+	output = strings.TrimRight(output, "\n")
+	lines := strings.Split(output, "\n")
+	if len(lines) <= maxTailLines {
+		return output
+	}
+	return fmt.Sprintf("... [%d earlier lines omitted]\n%s", len(lines)-maxTailLines, strings.Join(lines[len(lines)-maxTailLines:], "\n"))
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/icmd"
+)
+
+func TestJSONAppendsOutputFlagAndDecodes(t *testing.T) {
+	var got []string
+	restore := SetExecutor(ExecutorFunc(func(c icmd.Cmd) *Result {
+		got = c.Command
+		return NewResult(`{"metadata":{"name":"run-1"},"status":{"phase":"Done"}}`, "", 0, false)
+	}))
+	defer restore()
+
+	type object struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	obj, err := JSON[object]("tkn", "pr", "describe", "--last")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Metadata.Name != "run-1" {
+		t.Fatalf("decoded name = %q", obj.Metadata.Name)
+	}
+	if want := []string{"tkn", "pr", "describe", "--last", "-o", "json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("command = %#v, want %#v", got, want)
+	}
+
+	u, err := Unstructured("tkn", "pr", "describe", "--last", "--output=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.GetName() != "run-1" {
+		t.Fatalf("unstructured name = %q", u.GetName())
+	}
+	if want := []string{"tkn", "pr", "describe", "--last", "--output=json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("explicit output flag was not preserved: %#v", got)
+	}
+}
+
+func TestYAMLUsesJSONTags(t *testing.T) {
+	restore := SetExecutor(ExecutorFunc(func(icmd.Cmd) *Result {
+		return NewResult("spec:\n  targetNamespace: openshift-pipelines\n", "", 0, false)
+	}))
+	defer restore()
+
+	type config struct {
+		Spec struct {
+			TargetNamespace string `json:"targetNamespace"`
+		} `json:"spec"`
+	}
+	cfg, err := YAML[config]("oc", "get", "tektonconfig", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Spec.TargetNamespace != "openshift-pipelines" {
+		t.Fatalf("targetNamespace = %q", cfg.Spec.TargetNamespace)
+	}
+}
+
+func TestErrorCarriesCommandContext(t *testing.T) {
+	stderr := strings.Repeat("noise\n", 30) + "Error from server (NotFound): record not found\n"
+	restore := SetExecutor(ExecutorFunc(func(icmd.Cmd) *Result {
+		return NewResult("", stderr, 1, false)
+	}))
+	defer restore()
+
+	_, err := JSON[map[string]any]("opc", "results", "records", "get", "abc")
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("error %v is not a *cmd.Error", err)
+	}
+	if cmdErr.ExitCode != 1 || !strings.Contains(cmdErr.Error(), "opc results records get abc -o json: exit code 1") {
+		t.Fatalf("unexpected error: %v", cmdErr)
+	}
+	if !strings.HasPrefix(cmdErr.Stderr, "... [11 earlier lines omitted]") {
+		t.Fatalf("stderr was not trimmed to its tail: %q", cmdErr.Stderr)
+	}
+	if !StderrContains(err, "record not found") {
+		t.Fatal("StderrContains() did not find the NotFound message")
+	}
+}
+
+func TestDecodeFailureIncludesStdout(t *testing.T) {
+	restore := SetExecutor(ExecutorFunc(func(icmd.Cmd) *Result {
+		return NewResult("No PipelineRuns found", "", 0, false)
+	}))
+	defer restore()
+
+	_, err := JSON[map[string]any]("tkn", "pr", "list")
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) || cmdErr.Err == nil || cmdErr.Stdout != "No PipelineRuns found" {
+		t.Fatalf("unexpected decode error: %#v", err)
+	}
+}
+
+func TestWithOutputFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "no flag", args: []string{"tkn", "pr", "list"}, want: []string{"tkn", "pr", "list", "-o", "json"}},
+		{name: "short flag", args: []string{"tkn", "pr", "list", "-o", "yaml"}, want: []string{"tkn", "pr", "list", "-o", "yaml"}},
+		{name: "short flag with equals", args: []string{"tkn", "pr", "list", "-o=yaml"}, want: []string{"tkn", "pr", "list", "-o=yaml"}},
+		{name: "short flag joined", args: []string{"oc", "get", "pods", "-ojsonpath={.items}"}, want: []string{"oc", "get", "pods", "-ojsonpath={.items}"}},
+		{name: "long flag", args: []string{"tkn", "pr", "list", "--output=name"}, want: []string{"tkn", "pr", "list", "--output=name"}},
+		{name: "other -o flag", args: []string{"opc", "results", "list", "-oauth-token", "t"}, want: []string{"opc", "results", "list", "-oauth-token", "t", "-o", "json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withOutputFlag(tt.args, "json"); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("withOutputFlag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package cmd
+
+import "os/exec"
+
+// setProcessGroup is a no-op where process groups are unavailable.
+func setProcessGroup(*exec.Cmd) {}
+
+// killProcessGroup kills the command itself; its children may outlive it.
+func killProcessGroup(c *exec.Cmd) error {
+	return c.Process.Kill()
+}
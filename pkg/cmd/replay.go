@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gotest.tools/v3/icmd"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+// Interaction is one recorded command invocation and its outcome.
+type Interaction struct {
+	Args     []string `json:"args"`
+	Env      []string `json:"env,omitempty"` // only entries added on top of the parent environment
+	Stdin    string   `json:"stdin,omitempty"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr"`
+	ExitCode int      `json:"exitCode"`
+	Timeout  bool     `json:"timeout,omitempty"`
+}
+
+// redacted returns a copy of in with registered secrets and the values of
+// sensitive flags replaced, since fixtures are committed to testdata.
+func (in Interaction) redacted() Interaction {
+	out := in
+	out.Args = redact.Args(in.Args)
+	out.Env = nil
+	for _, entry := range in.Env {
+		out.Env = append(out.Env, redact.String(entry))
+	}
+	out.Stdin = redact.String(in.Stdin)
+	out.Stdout = redact.String(in.Stdout)
+	out.Stderr = redact.String(in.Stderr)
+	return out
+}
+
+// Fixture is the on-disk format shared by Recorder and Replayer.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an Executor that forwards commands to another Executor and captures
+// every invocation so it can be saved as a replay fixture.
+type Recorder struct {
+	path string
+	next Executor
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder writing to path. Commands are forwarded to next,
+// or to the default icmd backend when next is nil.
+func NewRecorder(path string, next Executor) *Recorder {
+	if next == nil {
+		next = IcmdExecutor{}
+	}
+	return &Recorder{path: path, next: next}
+}
+
+// Execute runs the command through the wrapped Executor and records it.
+func (r *Recorder) Execute(c icmd.Cmd) *Result {
+	return r.record(c, r.next.Execute)
+}
+
+// ExecuteContext records the command, letting the wrapped Executor observe ctx
+// when it supports it.
+func (r *Recorder) ExecuteContext(ctx context.Context, c icmd.Cmd) *Result {
+	if next, ok := r.next.(ContextExecutor); ok {
+		return r.record(c, func(c icmd.Cmd) *Result { return next.ExecuteContext(ctx, c) })
+	}
+	return r.record(c, r.next.Execute)
+}
+
+// record runs c with run and appends the outcome to the recorded interactions.
+func (r *Recorder) record(c icmd.Cmd, run func(icmd.Cmd) *Result) *Result {
+	var stdin string
+	if c.Stdin != nil {
+		data, err := io.ReadAll(c.Stdin)
+		if err != nil {
+			return NewResult("", fmt.Sprintf("recorder: failed to read stdin: %v", err), 127, false)
+		}
+		stdin = string(data)
+		c.Stdin = bytes.NewReader(data)
+	}
+
+	res := run(c)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Args:     fixtureArgs(c.Command),
+		Env:      addedEnv(c.Env),
+		Stdin:    stdin,
+		Stdout:   res.Stdout(),
+		Stderr:   res.Stderr(),
+		ExitCode: res.ExitCode,
+		Timeout:  res.Timeout,
+	})
+	return res
+}
+
+// Save writes all interactions recorded so far to the fixture file, with
+// secrets redacted.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	var fixture Fixture
+	for _, in := range r.interactions {
+		fixture.Interactions = append(fixture.Interactions, in.redacted())
+	}
+	r.mu.Unlock()
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode command fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0750); err != nil {
+		return fmt.Errorf("failed to create fixture directory for %s: %w", r.path, err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write command fixture %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// Replayer is an Executor that serves results from a fixture instead of running
+// processes. Each recorded interaction is served once, in recorded order among
+// interactions with the same argv, env and stdin.
+type Replayer struct {
+	path string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the fixture at path.
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: fixture path is chosen by test code
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command fixture %s: %w", path, err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode command fixture %s: %w", path, err)
+	}
+	return &Replayer{
+		path:         path,
+		interactions: fixture.Interactions,
+		used:         make([]bool, len(fixture.Interactions)),
+	}, nil
+}
+
+// Execute returns the next matching recorded result. The command is redacted the
+// way Save redacted the fixture before it is matched. An unmatched command yields
+// exit code 127 with an explanation on stderr, so assertions fail with context.
+func (r *Replayer) Execute(c icmd.Cmd) *Result {
+	var stdin string
+	if c.Stdin != nil {
+		data, err := io.ReadAll(c.Stdin)
+		if err != nil {
+			return NewResult("", fmt.Sprintf("replayer: failed to read stdin: %v", err), 127, false)
+		}
+		stdin = string(data)
+	}
+	want := Interaction{Args: fixtureArgs(c.Command), Env: addedEnv(c.Env), Stdin: stdin}.redacted()
+	args, env := want.Args, want.Env
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || !slices.Equal(in.Args, args) || !slices.Equal(in.Env, env) || in.Stdin != want.Stdin {
+			continue
+		}
+		r.used[i] = true
+		return NewResult(in.Stdout, in.Stderr, in.ExitCode, in.Timeout)
+	}
+	return NewResult("", fmt.Sprintf("replayer: no unused interaction in %s for %q", r.path, strings.Join(args, " ")), 127, false)
+}
+
+// Remaining returns the recorded interactions that have not been served yet.
+func (r *Replayer) Remaining() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var remaining []Interaction
+	for i, in := range r.interactions {
+		if !r.used[i] {
+			remaining = append(remaining, in)
+		}
+	}
+	return remaining
+}
+
+// fixtureArgs drops the connection flags Command injected from config.Flags so a
+// fixture recorded against one kubeconfig replays against any other.
+func fixtureArgs(args []string) []string {
+	if len(args) == 0 || (args[0] != "oc" && args[0] != "kubectl") {
+		return slices.Clone(args)
+	}
+	injected := map[string]string{
+		"--kubeconfig": config.Flags.Kubeconfig,
+		"--context":    config.Flags.Context,
+		"--cluster":    config.Flags.Cluster,
+	}
+	out := []string{args[0]}
+	rest := args[1:]
+	for len(rest) >= 2 {
+		value, ok := injected[rest[0]]
+		if !ok || value == "" || rest[1] != value {
+			break
+		}
+		delete(injected, rest[0])
+		rest = rest[2:]
+	}
+	return append(out, rest...)
+}
+
+// addedEnv returns the entries of env that are not already in the parent
+// environment, keeping fixtures small and free of unrelated host variables.
+func addedEnv(env []string) []string {
+	if env == nil {
+		return nil
+	}
+	parent := os.Environ()
+	var added []string
+	for _, entry := range env {
+		if !slices.Contains(parent, entry) {
+			added = append(added, entry)
+		}
+	}
+	return added
+}
+
+// Record installs a Recorder writing to path in front of the active Executor. The
+// returned stop function restores the previous Executor and saves the fixture.
+func Record(path string) (stop func() error) {
+	recorder := NewRecorder(path, CurrentExecutor())
+	restore := SetExecutor(recorder)
+	return func() error {
+		restore()
+		return recorder.Save()
+	}
+}
+
+// Replay installs a Replayer for the fixture at path. The returned restore
+// function reinstates the previous Executor.
+func Replay(path string) (*Replayer, func(), error) {
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return replayer, SetExecutor(replayer), nil
+}
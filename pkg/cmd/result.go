@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gotest.tools/v3/icmd"
+)
+
+// Result is the outcome of a command run through this package. It reads like an
+// icmd.Result, but this package owns it, so an Executor can serve canned output
+// without starting a process.
+type Result struct {
+	// Args is the command line that produced the result.
+	Args     []string
+	ExitCode int
+	Error    error
+	// Timeout is true if the command was killed because it ran for too long.
+	Timeout bool
+
+	stdout syncBuffer
+	stderr syncBuffer
+}
+
+// NewResult builds a Result without starting a process, for Executors that serve
+// canned output. A non-zero exitCode also sets Result.Error.
+func NewResult(stdout, stderr string, exitCode int, timeout bool) *Result {
+	res := &Result{ExitCode: exitCode, Timeout: timeout}
+	_, _ = res.stdout.WriteString(stdout)
+	_, _ = res.stderr.WriteString(stderr)
+	if exitCode != 0 {
+		res.Error = exitError(exitCode)
+	}
+	return res
+}
+
+// fromIcmd copies the outcome of a command icmd ran.
+func fromIcmd(args []string, r *icmd.Result) *Result {
+	res := NewResult(r.Stdout(), r.Stderr(), r.ExitCode, r.Timeout)
+	res.Args = args
+	res.Error = r.Error
+	return res
+}
+
+// Stdout returns the stdout of the command.
+func (r *Result) Stdout() string {
+	return r.stdout.String()
+}
+
+// Stderr returns the stderr of the command.
+func (r *Result) Stderr() string {
+	return r.stderr.String()
+}
+
+// Combined returns stdout and stderr concatenated.
+func (r *Result) Combined() string {
+	return r.Stdout() + r.Stderr()
+}
+
+// Compare checks the result against exp the way icmd.Result.Compare does and
+// returns an error listing every expectation that was not met.
+func (r *Result) Compare(exp icmd.Expected) error {
+	var failures []string
+	add := func(format string, args ...any) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+	if exp.ExitCode != r.ExitCode {
+		add("ExitCode was %d expected %d", r.ExitCode, exp.ExitCode)
+	}
+	if exp.Timeout != r.Timeout {
+		if exp.Timeout {
+			add("Expected command to timeout")
+		} else {
+			add("Expected command to finish, but it hit the timeout")
+		}
+	}
+	if !matchOutput(exp.Out, r.Stdout()) {
+		add("Expected stdout to contain %q", exp.Out)
+	}
+	if !matchOutput(exp.Err, r.Stderr()) {
+		add("Expected stderr to contain %q", exp.Err)
+	}
+	switch {
+	// A non-zero exit code always comes with an "exit status N" error.
+	case exp.Error == "" && exp.ExitCode != 0:
+	case exp.Error == "" && r.Error != nil:
+		add("Expected no error")
+	case exp.Error != "" && r.Error == nil:
+		add("Expected error to contain %q, but there was no error", exp.Error)
+	case exp.Error != "" && !strings.Contains(r.Error.Error(), exp.Error):
+		add("Expected error to contain %q", exp.Error)
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s\nFailures:\n%s", r, strings.Join(failures, "\n"))
+}
+
+func matchOutput(expected, actual string) bool {
+	if expected == icmd.None {
+		return actual == ""
+	}
+	return strings.Contains(actual, expected)
+}
+
+// String describes the command and its outcome.
+func (r *Result) String() string {
+	var timeout, errString string
+	if r.Timeout {
+		timeout = " (timeout)"
+	}
+	if r.Error != nil {
+		errString = "\nError:    " + r.Error.Error()
+	}
+	return fmt.Sprintf("\nCommand:  %s\nExitCode: %d%s%s\nStdout:   %v\nStderr:   %v\n",
+		strings.Join(r.Args, " "), r.ExitCode, timeout, errString, r.Stdout(), r.Stderr())
+}
+
+// syncBuffer is a bytes.Buffer safe for a process writing while a caller reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) WriteString(s string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.WriteString(s)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
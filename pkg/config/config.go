@@ -240,6 +240,7 @@ type EnvironmentFlags struct {
 	Context                      string      // K8s context override
 	SpokeKubeconfigs             StringArray // Path to Spoke kubeconfig (No Defaults)
 	SpokeContexts                StringArray // Name of the  Spoke Context (defaults to CurrentContext from SpokeKubeconfig)
+	Profile                      string      // Properties profile layered over env/default (defaults to $TEST_PROFILE)
 	DockerRepo                   string      // Docker repo (defaults to $KO_DOCKER_REPO)
 	CSV                          string      // Default csv openshift-pipelines-operator.v0.9.1
 	Channel                      string      // Default channel canary
@@ -250,6 +251,7 @@ type EnvironmentFlags struct {
 	TknVersion                   string
 	ClusterArch                  string // Architecture of the cluster
 	IsDisconnected               bool
+	FailOnOperatorDrift          bool // Fail the suite when hooks.OperatorHealthDiff finds changes
 	KueueOperatorNamespace       string
 	PipelinesOperatorNamespace   string
 	CertManagerOperatorNamespace string
@@ -259,8 +261,12 @@ type EnvironmentFlags struct {
 }
 
 func initializeFlags() *EnvironmentFlags {
-	LoadDefaultProperties()
 	var f EnvironmentFlags
+	f.Profile = selectedProfile()
+	LoadDefaultProperties()
+	flag.StringVar(&f.Profile, "profile", f.Profile,
+		"Provide the properties profile (env/<profile>) to layer over env/default. Defaults to $"+ProfileEnv+".")
+
 	flag.StringVar(&f.Cluster, "cluster", "",
 		"Provide the cluster to test against. Defaults to the current cluster in kubeconfig.")
 
@@ -354,6 +360,10 @@ func initializeFlags() *EnvironmentFlags {
 	flag.BoolVar(&f.IsDisconnected, "isdisconnected", defaultIsDiconnected,
 		"Provide the info if the testing cluster is disconnected. By default `false` will be used.")
 
+	defaultFailOnOperatorDrift, _ := strconv.ParseBool(os.Getenv("FAIL_ON_OPERATOR_DRIFT"))
+	flag.BoolVar(&f.FailOnOperatorDrift, "fail-on-operator-drift", defaultFailOnOperatorDrift,
+		"Fail the suite when it leaves the operator-managed cluster state changed. Defaults to $FAIL_ON_OPERATOR_DRIFT, else only report it.")
+
 	// Preserve the existing environment-backed defaults for callers that read Flags before parsing.
 	f.DockerRepo = defaultRepo
 	f.Channel = defaultChannel
@@ -365,36 +375,26 @@ func initializeFlags() *EnvironmentFlags {
 	f.TknVersion = defaultTkn
 	f.ClusterArch = defaultClusterArch
 	f.IsDisconnected = defaultIsDiconnected
+	f.FailOnOperatorDrift = defaultFailOnOperatorDrift
 
 	return &f
 }
 
-// LoadDefaultProperties reads env/default/default.properties and sets each key as an
-// environment variable, but only when the variable is not already set. This replicates
-// Gauge's automatic loading of env/default/*.properties before spec execution.
+// LoadDefaultProperties loads env/default/*.properties, and the profile named by
+// -profile or TEST_PROFILE on top, and sets each key as an environment variable
+// unless the variable is already set. This replicates Gauge's automatic loading
+// of env/default/*.properties before spec execution. Errors are only logged;
+// suites see them again from MustLoadEnvironment.
 func LoadDefaultProperties() {
-	envFile := filepath.Join(Dir(), "..", "env", "default", "default.properties")
-	data, err := os.ReadFile(envFile)
+	p, err := LoadProperties(envDir(), selectedProfile())
+	if err == nil {
+		err = p.Apply()
+	}
 	if err != nil {
+		log.Printf("warning: failed to load properties: %v", err)
 		return
 	}
-	for _, line := range strings.Split(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		if os.Getenv(key) == "" {
-			if err := os.Setenv(key, value); err != nil {
-				log.Printf("warning: failed to set env %s: %v", key, err)
-			}
-		}
-	}
+	Effective = p
 }
 
 // Dir returns the absolute path to the template directory.
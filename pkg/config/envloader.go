@@ -1,77 +1,83 @@
 package config
 
 import (
-	"bufio"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 )
 
-// LoadEnvironmentFromProperties loads environment variables from properties file
-func LoadEnvironmentFromProperties() error {
-	// Get the project root directory
-	_, b, _, _ := runtime.Caller(0)
-	projectRoot := filepath.Join(filepath.Dir(b), "..", "..")
-	propertiesFile := filepath.Join(projectRoot, "env", "default", "default.properties")
+// Effective holds the properties loaded for this run, or nil if loading failed.
+var Effective *Properties
 
-	// Check if properties file exists
-	if _, err := os.Stat(propertiesFile); os.IsNotExist(err) {
-		// Properties file doesn't exist, continue without error
-		// This allows tests to run without the file if env vars are set externally
-		return nil
+// LoadEnvironmentFromProperties loads the properties of the profile selected
+// with -profile or TEST_PROFILE, layered over env/default, and exports them as
+// environment variables. Variables already set in the environment win.
+func LoadEnvironmentFromProperties() error {
+	p, err := LoadProperties(envDir(), Flags.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to load properties: %w", err)
 	}
+	if err := p.Apply(); err != nil {
+		return err
+	}
+	Effective = p
+	return nil
+}
 
-	file, err := os.Open(propertiesFile) //nolint:gosec // G304: file path comes from config, not user input
+// MustLoadEnvironment loads environment from properties files, checks them
+// against the Schema of the given suite labels and logs the effective values,
+// or panics.
+func MustLoadEnvironment(labels ...string) {
+	err := LoadEnvironmentFromProperties()
+	if err == nil {
+		log.Print(Effective.Report(labels...))
+		err = Effective.Check(labels...)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to open properties file %s: %w", propertiesFile, err)
+		panic(fmt.Sprintf("Failed to load environment: %v", err))
 	}
-	defer func() { _ = file.Close() }()
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+// GetPropertiesFilePath returns the path to the properties file
+func GetPropertiesFilePath() string {
+	return filepath.Join(envDir(), DefaultProfile, "default.properties")
+}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
+// envDir returns the directory holding one properties directory per profile.
+func envDir() string {
+	_, b, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(b), "..", "..", "env")
+}
 
-		// Parse key = value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
+// profileFromArgs returns the value of a -profile flag in args. Flags are
+// parsed after package initialization, but the properties have to be loaded
+// before it because the flag defaults are read from them.
+func profileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "profile" {
 			continue
 		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Only set if not already set (allows override from CI/CD)
-		if os.Getenv(key) == "" {
-			if err := os.Setenv(key, value); err != nil {
-				return fmt.Errorf("failed to set env var %s: %w", key, err)
-			}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
 		}
 	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading properties file: %w", err)
-	}
-
-	return nil
+	return ""
 }
 
-// MustLoadEnvironment loads environment from properties file or panics
-func MustLoadEnvironment() {
-	if err := LoadEnvironmentFromProperties(); err != nil {
-		panic(fmt.Sprintf("Failed to load environment: %v", err))
+// selectedProfile returns the profile named on the command line or in ProfileEnv.
+func selectedProfile() string {
+	if profile := profileFromArgs(os.Args[1:]); profile != "" {
+		return profile
 	}
-}
-
-// GetPropertiesFilePath returns the path to the properties file
-func GetPropertiesFilePath() string {
-	_, b, _, _ := runtime.Caller(0)
-	projectRoot := filepath.Join(filepath.Dir(b), "..", "..")
-	return filepath.Join(projectRoot, "env", "default", "default.properties")
+	return os.Getenv(ProfileEnv)
 }
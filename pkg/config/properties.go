@@ -0,0 +1,313 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+// ProfileEnv selects the properties profile when the -profile flag is not given.
+const ProfileEnv = "TEST_PROFILE"
+
+// DefaultProfile is the profile every other profile is layered over.
+const DefaultProfile = "default"
+
+// Source tells where the effective value of a property came from.
+type Source string
+
+const (
+	// SourceEnv values were set in the environment before the tests started,
+	// e.g. by CI, and win over every properties file.
+	SourceEnv Source = "env"
+	// SourceProfile values come from env/<profile>/*.properties.
+	SourceProfile Source = "profile"
+	// SourceDefault values come from env/default/*.properties.
+	SourceDefault Source = "default"
+)
+
+// Property is the effective value of one key.
+type Property struct {
+	Key    string
+	Value  string
+	Source Source
+	// File and Line locate the definition for profile and default values.
+	File string
+	Line int
+}
+
+func (p Property) origin() string {
+	if p.File == "" {
+		return string(p.Source)
+	}
+	return fmt.Sprintf("%s, %s:%d", p.Source, p.File, p.Line)
+}
+
+// Properties are the layered properties of a profile: the environment over
+// env/<profile> over env/default.
+type Properties struct {
+	Profile string
+	values  map[string]Property
+}
+
+// applied remembers the environment variables Apply set, so a later load does
+// not mistake them for values CI put in the environment.
+var applied = struct {
+	sync.Mutex
+	values map[string]string
+}{values: map[string]string{}}
+
+// interpolation matches ${VAR} references in property values.
+var interpolation = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// LoadProperties reads envDir/default/*.properties and, for any other profile,
+// envDir/<profile>/*.properties on top of it, then lets non-empty environment
+// variables override both. ${VAR} in a value refers to another property or
+// environment variable. Malformed lines, a missing profile and undefined or
+// cyclic references are errors.
+func LoadProperties(envDir, profile string) (*Properties, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	p := &Properties{Profile: profile, values: map[string]Property{}}
+
+	layers := []Source{SourceDefault}
+	if profile != DefaultProfile {
+		layers = append(layers, SourceProfile)
+	}
+	var errs []error
+	for _, source := range layers {
+		dir := filepath.Join(envDir, DefaultProfile)
+		if source == SourceProfile {
+			dir = filepath.Join(envDir, profile)
+			if _, err := os.Stat(dir); err != nil {
+				return nil, fmt.Errorf("properties profile %q: %w", profile, err)
+			}
+		}
+		files, err := filepath.Glob(filepath.Join(dir, "*.properties"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			errs = append(errs, p.readFile(file, source))
+		}
+	}
+
+	applied.Lock()
+	for key, prop := range p.values {
+		if value := os.Getenv(key); value != "" && applied.values[key] != value {
+			p.values[key] = Property{Key: key, Value: value, Source: SourceEnv}
+		} else {
+			p.values[key] = prop
+		}
+	}
+	applied.Unlock()
+
+	errs = append(errs, p.interpolate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// readFile adds the key = value lines of file to p, overriding earlier layers.
+func (p *Properties) readFile(file string, source Source) error {
+	f, err := os.Open(file) //nolint:gosec // G304: properties files live in the repo
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			errs = append(errs, fmt.Errorf("%s:%d: want KEY = value, got %q", file, line, text))
+			continue
+		}
+		p.values[key] = Property{Key: key, Value: strings.TrimSpace(value), Source: source, File: file, Line: line}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("error reading properties file %s: %w", file, err))
+	}
+	return errors.Join(errs...)
+}
+
+// interpolate resolves ${VAR} references in every property value.
+func (p *Properties) interpolate() error {
+	resolved := map[string]bool{}
+	var resolve func(key string, visiting []string) error
+	resolve = func(key string, visiting []string) error {
+		if resolved[key] || p.values[key].Source == SourceEnv {
+			return nil
+		}
+		if slices.Contains(visiting, key) {
+			return fmt.Errorf("%s: cyclic reference %s", key, strings.Join(append(visiting, key), " -> "))
+		}
+		prop := p.values[key]
+		var errs []error
+		prop.Value = interpolation.ReplaceAllStringFunc(prop.Value, func(ref string) string {
+			name := interpolation.FindStringSubmatch(ref)[1]
+			if _, ok := p.values[name]; ok {
+				if err := resolve(name, append(visiting, key)); err != nil {
+					errs = append(errs, err)
+					return ref
+				}
+				return p.values[name].Value
+			}
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+			errs = append(errs, fmt.Errorf("%s (%s): ${%s} is not defined", key, prop.origin(), name))
+			return ref
+		})
+		p.values[key] = prop
+		resolved[key] = true
+		return errors.Join(errs...)
+	}
+
+	var errs []error
+	for _, key := range p.Keys() {
+		errs = append(errs, resolve(key, nil))
+	}
+	return errors.Join(errs...)
+}
+
+// Keys returns the defined keys in sorted order.
+func (p *Properties) Keys() []string {
+	keys := make([]string, 0, len(p.values))
+	for key := range p.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Get returns the effective property for key.
+func (p *Properties) Get(key string) (Property, bool) {
+	prop, ok := p.values[key]
+	return prop, ok
+}
+
+// Apply exports every property that does not come from the environment.
+func (p *Properties) Apply() error {
+	applied.Lock()
+	defer applied.Unlock()
+	for _, key := range p.Keys() {
+		prop := p.values[key]
+		if prop.Source == SourceEnv {
+			continue
+		}
+		if err := os.Setenv(key, prop.Value); err != nil {
+			return fmt.Errorf("failed to set env var %s: %w", key, err)
+		}
+		applied.values[key] = prop.Value
+	}
+	return nil
+}
+
+// Requirement declares a property a suite cannot run without.
+type Requirement struct {
+	Key string
+	// Pattern, when set, must match the whole value.
+	Pattern *regexp.Regexp
+}
+
+// versionPattern matches the major.minor versions the version checks compare
+// against, with an optional patch and v prefix.
+var versionPattern = regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?$`)
+
+// Schema lists the required properties per suite label. Suites pass their
+// label to MustLoadEnvironment to fail at start-up instead of skipping specs.
+var Schema = map[string][]Requirement{
+	"versions": {
+		{Key: "PIPELINE_VERSION", Pattern: versionPattern},
+		{Key: "TRIGGERS_VERSION", Pattern: versionPattern},
+		{Key: "OPERATOR_VERSION", Pattern: versionPattern},
+		{Key: "CHAINS_VERSION", Pattern: versionPattern},
+		{Key: "PAC_VERSION", Pattern: versionPattern},
+		{Key: "HUB_VERSION", Pattern: versionPattern},
+		{Key: "RESULTS_VERSION", Pattern: versionPattern},
+		{Key: "MANUAL_APPROVAL_VERSION", Pattern: versionPattern},
+		{Key: "OSP_VERSION", Pattern: versionPattern},
+		{Key: "TKN_CLIENT_VERSION", Pattern: versionPattern},
+	},
+	"olm": {
+		{Key: "CHANNEL"},
+		{Key: "CATALOG_SOURCE"},
+		{Key: "SUBSCRIPTION_NAME"},
+	},
+}
+
+// Check validates the properties against the Schema of every label.
+func (p *Properties) Check(labels ...string) error {
+	var errs []error
+	for _, label := range labels {
+		for _, req := range Schema[label] {
+			prop, ok := p.lookup(req.Key)
+			switch {
+			case !ok || prop.Value == "":
+				errs = append(errs, fmt.Errorf("%s is required by the %s suite but not set", req.Key, label))
+			case req.Pattern != nil && !req.Pattern.MatchString(prop.Value):
+				errs = append(errs, fmt.Errorf("%s = %q (%s) does not match %s, required by the %s suite",
+					req.Key, prop.Value, prop.origin(), req.Pattern, label))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookup returns the property for key, falling back to the environment for
+// keys no properties file defines.
+func (p *Properties) lookup(key string) (Property, bool) {
+	if prop, ok := p.values[key]; ok {
+		return prop, true
+	}
+	if value := os.Getenv(key); value != "" {
+		return Property{Key: key, Value: value, Source: SourceEnv}, true
+	}
+	return Property{}, false
+}
+
+// Report lists every effective value with its source, plus the keys labels
+// require, with secrets redacted.
+func (p *Properties) Report(labels ...string) string {
+	keys := p.Keys()
+	for _, label := range labels {
+		for _, req := range Schema[label] {
+			if !slices.Contains(keys, req.Key) {
+				keys = append(keys, req.Key)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	width := 0
+	for _, key := range keys {
+		width = max(width, len(key))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "properties profile %q:\n", p.Profile)
+	for _, key := range keys {
+		prop, ok := p.lookup(key)
+		if !ok {
+			fmt.Fprintf(&sb, "  %-*s   (not set)\n", width, key)
+			continue
+		}
+		fmt.Fprintf(&sb, "  %-*s = %s   (%s)\n", width, key, prop.Value, prop.origin())
+	}
+	return redact.String(sb.String())
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeProperties creates envDir/<profile>/<file> with lines.
+func writeProperties(t *testing.T, envDir, profile, file string, lines ...string) {
+	t.Helper()
+	dir := filepath.Join(envDir, profile)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPropertiesLayers(t *testing.T) {
+	envDir := t.TempDir()
+	writeProperties(t, envDir, DefaultProfile, "default.properties",
+		"# versions",
+		"TEST_PROPS_PIPELINE = v1.12",
+		"TEST_PROPS_CHANNEL = latest",
+		"TEST_PROPS_CI = from-file",
+	)
+	writeProperties(t, envDir, "nightly", "versions.properties",
+		"TEST_PROPS_PIPELINE = v1.13",
+	)
+	t.Setenv("TEST_PROPS_CI", "from-env")
+
+	p, err := LoadProperties(envDir, "nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key    string
+		value  string
+		source Source
+		line   int
+	}{
+		{key: "TEST_PROPS_PIPELINE", value: "v1.13", source: SourceProfile, line: 1},
+		{key: "TEST_PROPS_CHANNEL", value: "latest", source: SourceDefault, line: 3},
+		{key: "TEST_PROPS_CI", value: "from-env", source: SourceEnv},
+	}
+	for _, tt := range tests {
+		prop, ok := p.Get(tt.key)
+		if !ok {
+			t.Fatalf("Get(%s) found nothing", tt.key)
+		}
+		if prop.Value != tt.value || prop.Source != tt.source || prop.Line != tt.line {
+			t.Fatalf("Get(%s) = %+v, want %s from %s line %d", tt.key, prop, tt.value, tt.source, tt.line)
+		}
+	}
+	want := []string{"TEST_PROPS_CHANNEL", "TEST_PROPS_CI", "TEST_PROPS_PIPELINE"}
+	if got := p.Keys(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadPropertiesApplyKeepsSources(t *testing.T) {
+	envDir := t.TempDir()
+	writeProperties(t, envDir, DefaultProfile, "default.properties", "TEST_PROPS_APPLIED = v1.12")
+	t.Setenv("TEST_PROPS_APPLIED", "")
+
+	p, err := LoadProperties(envDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("TEST_PROPS_APPLIED"); got != "v1.12" {
+		t.Fatalf("TEST_PROPS_APPLIED = %q after Apply", got)
+	}
+
+	p, err = LoadProperties(envDir, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prop, _ := p.Get("TEST_PROPS_APPLIED"); prop.Source != SourceDefault {
+		t.Fatalf("reloaded source = %s, want %s rather than the value Apply exported", prop.Source, SourceDefault)
+	}
+}
+
+func TestLoadPropertiesInterpolation(t *testing.T) {
+	envDir := t.TempDir()
+	writeProperties(t, envDir, DefaultProfile, "default.properties",
+		"TEST_PROPS_IMAGE = ${TEST_PROPS_REGISTRY}/tekton:${TEST_PROPS_TAG}",
+		"TEST_PROPS_REGISTRY = ${TEST_PROPS_HOST}/pipelines",
+		"TEST_PROPS_TAG = v1",
+	)
+	t.Setenv("TEST_PROPS_HOST", "quay.io")
+
+	p, err := LoadProperties(envDir, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prop, _ := p.Get("TEST_PROPS_IMAGE"); prop.Value != "quay.io/pipelines/tekton:v1" {
+		t.Fatalf("TEST_PROPS_IMAGE = %q", prop.Value)
+	}
+}
+
+func TestLoadPropertiesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		profile string
+		wantErr string
+	}{{
+		name:    "malformed line",
+		lines:   []string{"TEST_PROPS_OK = 1", "TEST_PROPS_BROKEN"},
+		wantErr: `default.properties:2: want KEY = value, got "TEST_PROPS_BROKEN"`,
+	}, {
+		name:    "key with spaces",
+		lines:   []string{"TEST PROPS = 1"},
+		wantErr: "default.properties:1:",
+	}, {
+		name:    "undefined reference",
+		lines:   []string{"TEST_PROPS_URL = https://${TEST_PROPS_UNDEFINED}"},
+		wantErr: "${TEST_PROPS_UNDEFINED} is not defined",
+	}, {
+		name:    "cyclic reference",
+		lines:   []string{"TEST_PROPS_A = ${TEST_PROPS_B}", "TEST_PROPS_B = ${TEST_PROPS_A}"},
+		wantErr: "cyclic reference TEST_PROPS_A -> TEST_PROPS_B -> TEST_PROPS_A",
+	}, {
+		name:    "missing profile",
+		profile: "nightly",
+		wantErr: `properties profile "nightly"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envDir := t.TempDir()
+			writeProperties(t, envDir, DefaultProfile, "default.properties", tt.lines...)
+			_, err := LoadProperties(envDir, tt.profile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadProperties() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPropertiesCheck(t *testing.T) {
+	envDir := t.TempDir()
+	writeProperties(t, envDir, DefaultProfile, "default.properties",
+		"CHANNEL = latest",
+		"CATALOG_SOURCE = redhat-operators",
+		"SUBSCRIPTION_NAME = openshift-pipelines-operator-rh",
+		"PIPELINE_VERSION = 1.12.x",
+	)
+	for _, key := range []string{"CHANNEL", "CATALOG_SOURCE", "SUBSCRIPTION_NAME", "PIPELINE_VERSION", "TRIGGERS_VERSION"} {
+		t.Setenv(key, "")
+	}
+	p, err := LoadProperties(envDir, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Check("olm"); err != nil {
+		t.Fatalf("Check(olm) = %v", err)
+	}
+	err = p.Check("versions")
+	if err == nil {
+		t.Fatal("Check(versions) = nil")
+	}
+	for _, want := range []string{
+		`PIPELINE_VERSION = "1.12.x" (default, ` + filepath.Join(envDir, DefaultProfile, "default.properties") + `:4) does not match`,
+		"TRIGGERS_VERSION is required by the versions suite but not set",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Check(versions) = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestPropertiesReport(t *testing.T) {
+	envDir := t.TempDir()
+	writeProperties(t, envDir, DefaultProfile, "default.properties",
+		"TEST_PROPS_CHANNEL = latest",
+		"TEST_PROPS_TOKEN = set-by-ci",
+	)
+	t.Setenv("TEST_PROPS_TOKEN", "s3cr3t-value")
+	t.Setenv("CHANNEL", "stable")
+	t.Setenv("CATALOG_SOURCE", "")
+	t.Setenv("SUBSCRIPTION_NAME", "")
+	p, err := LoadProperties(envDir, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := p.Report("olm")
+	for _, want := range []string{
+		`properties profile "default"`,
+		"CHANNEL            = stable   (env)",
+		"CATALOG_SOURCE       (not set)",
+		"TEST_PROPS_CHANNEL = latest   (default, ",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("Report() = %s\nwant it to contain %q", report, want)
+		}
+	}
+	if strings.Contains(report, "s3cr3t-value") {
+		t.Fatalf("Report() leaks a token:\n%s", report)
+	}
+}
+
+func TestProfileFromArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"-test.v", "-profile", "nightly"}, want: "nightly"},
+		{args: []string{"--profile=nightly"}, want: "nightly"},
+		{args: []string{"-kubeconfig=/tmp/hub"}, want: ""},
+		{args: []string{"--", "-profile", "nightly"}, want: ""},
+		{args: []string{"-profile"}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := profileFromArgs(tt.args); got != tt.want {
+			t.Fatalf("profileFromArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
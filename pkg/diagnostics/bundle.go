@@ -0,0 +1,263 @@
+package diagnostics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
+)
+
+// ArtifactsDirEnv names the directory CI uploads as job artifacts. Failure bundles
+// are written below it in a "diagnostics" directory.
+const ArtifactsDirEnv = "ARTIFACTS_DIR"
+
+// maxBundleDirLength keeps bundle directory names well below filesystem limits.
+const maxBundleDirLength = 80
+
+// BundleFile describes one file of a failure bundle in its index.json.
+type BundleFile struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Bytes       int    `json:"bytes"`
+	// Error is set when the content could not be collected.
+	Error string `json:"error,omitempty"`
+}
+
+// BundleIndex is written as index.json at the root of every failure bundle.
+type BundleIndex struct {
+	Spec        string       `json:"spec"`
+	Location    string       `json:"location"`
+	State       string       `json:"state"`
+	Failure     string       `json:"failure,omitempty"`
+	Attempt     int          `json:"attempt"`
+	Namespace   string       `json:"namespace,omitempty"`
+	CollectedAt time.Time    `json:"collectedAt"`
+	Files       []BundleFile `json:"files"`
+	// Errors lists what could not be collected, e.g. kinds whose CRD is not installed.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// WriteBundleOnFailure returns a function suitable for use with ReportAfterEach.
+// When a spec fails and ARTIFACTS_DIR is set it writes a failure bundle for the
+// spec's namespace (see WriteBundle) and adds its path to the report as a
+// "diagnostics-bundle" entry.
+//
+// Usage:
+//
+//	var _ = ReportAfterEach(diagnostics.WriteBundleOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+func WriteBundleOnFailure(namespace *string, clientsFunc func() *clients.Clients) func(SpecReport) {
+	return func(report SpecReport) {
+		root := os.Getenv(ArtifactsDirEnv)
+		if !report.Failed() || root == "" {
+			return
+		}
+		cs := clientsFunc()
+		if cs == nil {
+			return
+		}
+		ns := ""
+		if namespace != nil {
+			ns = *namespace
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+		defer cancel()
+		dir, err := WriteBundle(ctx, filepath.Join(root, "diagnostics"), report, NewCollector(cs), ns)
+		if err != nil {
+			AddReportEntry("diagnostics-bundle", fmt.Sprintf("[error writing bundle: %v]", err))
+			return
+		}
+		AddReportEntry("diagnostics-bundle", dir)
+	}
+}
+
+// WriteBundle saves everything needed to triage a failed spec without cluster
+// access into a new directory below root, and returns that directory:
+//
+//	index.json                            spec, failure and the list of files below
+//	events.txt                            namespace events, oldest first
+//	conditions.txt                        conditions and events of every object
+//	resources/<kind>.yaml                 every Tekton object, pod, service and config map
+//	pods/<pod>/<container>.log            logs of init, step and sidecar containers
+//	pods/<pod>/<container>.previous.log   logs of the previous, restarted container
+//	operator/resources/<kind>.yaml        TektonConfig and component status, installer sets, pods
+//	operator/pods.txt                     operator pods and their restart counts
+//	operator/pods/<pod>/<container>.log   operator logs
+//
+// collector decides how much is kept; NewCollector keeps everything. Every file
+// is redacted. Items that cannot be collected are listed in index.json rather
+// than aborting the bundle; only failures to write below root are returned. An
+// empty namespace collects the operator status only.
+func WriteBundle(ctx context.Context, root string, report SpecReport, collector *Collector, namespace string) (string, error) {
+	dir := filepath.Join(root, bundleDirName(report))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+	b := &bundle{dir: dir, index: BundleIndex{
+		Spec:        report.FullText(),
+		Location:    report.LeafNodeLocation.String(),
+		State:       report.State.String(),
+		Failure:     redact.String(report.Failure.Message),
+		Attempt:     report.NumAttempts,
+		Namespace:   namespace,
+		CollectedAt: time.Now().UTC(),
+	}}
+
+	if namespace != "" {
+		b.collection("", collector.CollectNamespace(ctx, namespace))
+	}
+	b.collection("operator", collector.CollectOperator(ctx, config.Flags.PipelinesOperatorNamespace))
+
+	data, err := json.MarshalIndent(b.index, "", "  ")
+	if err != nil {
+		return dir, fmt.Errorf("failed to encode bundle index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), data, 0o600); err != nil {
+		return dir, fmt.Errorf("failed to write bundle index: %w", err)
+	}
+	return dir, errors.Join(b.writeErrs...)
+}
+
+// bundle accumulates the files of one failure bundle.
+type bundle struct {
+	dir       string
+	index     BundleIndex
+	writeErrs []error
+}
+
+// collection writes the events, resources and logs of col below prefix.
+func (b *bundle) collection(prefix string, col *Collection) {
+	for _, e := range col.Errors {
+		if prefix != "" {
+			e = prefix + ": " + e
+		}
+		b.index.Errors = append(b.index.Errors, redact.String(e))
+	}
+
+	if prefix == "" {
+		var events strings.Builder
+		for _, e := range col.Events {
+			events.WriteString(e.String() + "\n")
+		}
+		b.write("events.txt", "namespace events", events.String(), "")
+		b.write("conditions.txt", "conditions and events of every object", conditionSummary(col), "")
+	}
+
+	var kinds []string
+	for _, r := range col.Resources {
+		if !slices.Contains(kinds, r.Kind) {
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	for _, kind := range kinds {
+		var docs []string
+		for _, r := range col.ResourcesOf(kind) {
+			data, err := yaml.Marshal(r.Object)
+			if err != nil {
+				docs = append(docs, fmt.Sprintf("# %s/%s: %v\n", kind, r.Name, err))
+				continue
+			}
+			docs = append(docs, string(data))
+		}
+		b.write(path.Join(prefix, "resources", strings.ToLower(kind)+".yaml"), kind, strings.Join(docs, "---\n"), "")
+	}
+	if prefix != "" {
+		b.write(path.Join(prefix, "pods.txt"), "pods and their restart counts", podSummary(col), "")
+	}
+
+	for _, log := range col.Logs {
+		name := log.Container + ".log"
+		description := fmt.Sprintf("logs of %s/%s", log.Pod, log.Container)
+		if log.Previous {
+			name = log.Container + ".previous.log"
+			description = fmt.Sprintf("previous logs of %s/%s (%d restarts)", log.Pod, log.Container, log.Restarts)
+		}
+		b.write(path.Join(prefix, "pods", log.Pod, name), description, log.Log, log.Error)
+	}
+}
+
+// write saves content under name and records it in the index.
+func (b *bundle) write(name, description, content, collectErr string) {
+	content = redact.String(content)
+	file := BundleFile{Path: name, Description: description, Bytes: len(content), Error: redact.String(collectErr)}
+	full := filepath.Join(b.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+		b.writeErrs = append(b.writeErrs, err)
+		return
+	}
+	if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+		b.writeErrs = append(b.writeErrs, err)
+		return
+	}
+	b.index.Files = append(b.index.Files, file)
+}
+
+// conditionSummary renders the conditions and events of every collected object, the
+// part of "oc describe" that matters for triage.
+func conditionSummary(col *Collection) string {
+	var sb strings.Builder
+	for _, r := range col.Resources {
+		fmt.Fprintf(&sb, "%s/%s\n", r.Kind, r.Name)
+		for _, condition := range r.Conditions {
+			fmt.Fprintf(&sb, "  condition: %s\n", condition)
+		}
+		for _, e := range col.Events {
+			if e.Object == r.Kind+"/"+r.Name {
+				fmt.Fprintf(&sb, "  event: %s\n", e)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// podSummary lists pods with their phase and container restart counts.
+func podSummary(col *Collection) string {
+	var sb strings.Builder
+	for _, pod := range col.pods() {
+		fmt.Fprintf(&sb, "%s %s", pod.Name, pod.Status.Phase)
+		for _, ctr := range containersOf(&pod) {
+			fmt.Fprintf(&sb, " %s=%d", ctr.name, ctr.restarts)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// unsafePathChars matches everything not kept in bundle directory names.
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// bundleDirName derives a readable, unique directory name for a spec attempt:
+// the slugged spec text plus a short hash of the full text and location, so
+// specs with the same text in different files do not collide.
+func bundleDirName(report SpecReport) string {
+	slug := strings.Trim(unsafePathChars.ReplaceAllString(report.FullText(), "-"), "-.")
+	if len(slug) > maxBundleDirLength {
+		slug = slug[:maxBundleDirLength]
+	}
+	if slug == "" {
+		slug = "spec"
+	}
+	sum := sha256.Sum256([]byte(report.FullText() + "\x00" + report.LeafNodeLocation.String()))
+	name := slug + "-" + hex.EncodeToString(sum[:4])
+	if report.NumAttempts > 1 {
+		name += fmt.Sprintf("-attempt-%d", report.NumAttempts)
+	}
+	return name
+}
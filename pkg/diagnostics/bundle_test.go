@@ -0,0 +1,113 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+func TestWriteBundle(t *testing.T) {
+	collector := fakeCollector()
+	redact.Register("s3cr3t-value")
+	if _, err := collector.Kube.CoreV1().ConfigMaps("demo").Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "demo"},
+		Data:       map[string]string{"token": "s3cr3t-value"},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	report := types.SpecReport{
+		ContainerHierarchyTexts: []string{"PIPELINES-01"},
+		LeafNodeText:            "runs a pipeline",
+		LeafNodeLocation:        types.CodeLocation{FileName: "pipelines_test.go", LineNumber: 42},
+		State:                   types.SpecStateFailed,
+		NumAttempts:             1,
+		Failure:                 types.Failure{Message: "PipelineRun failed"},
+	}
+
+	dir, err := WriteBundle(context.Background(), t.TempDir(), report, collector, "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(filepath.Base(dir), "PIPELINES-01-runs-a-pipeline-") {
+		t.Fatalf("bundle directory %q is not named after the spec", dir)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index BundleIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Spec != "PIPELINES-01 runs a pipeline" || index.Failure != "PipelineRun failed" || index.Namespace != "demo" {
+		t.Fatalf("unexpected index header: %+v", index)
+	}
+	files := map[string]BundleFile{}
+	for _, f := range index.Files {
+		files[f.Path] = f
+	}
+	for _, path := range []string{
+		"events.txt",
+		"conditions.txt",
+		"resources/pipelinerun.yaml",
+		"resources/configmap.yaml",
+		"pods/build-pod/prepare.log",
+		"pods/build-pod/step-compile.log",
+		"pods/build-pod/step-compile.previous.log",
+		"operator/resources/tektonconfig.yaml",
+		"operator/pods.txt",
+		"operator/pods/tekton-operator/operator.log",
+	} {
+		if _, ok := files[path]; !ok {
+			t.Errorf("index has no %s; files: %v", path, slices.Sorted(maps.Keys(files)))
+		}
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("missing file: %v", err)
+		}
+	}
+	if _, ok := files["pods/build-pod/prepare.previous.log"]; ok {
+		t.Error("previous logs collected for a container that never restarted")
+	}
+	if len(index.Errors) != 1 || !strings.HasPrefix(index.Errors[0], "ApprovalTask: ") {
+		t.Errorf("missing CRD not recorded in the index: %v", index.Errors)
+	}
+
+	conditions, err := os.ReadFile(filepath.Join(dir, "conditions.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(conditions), "Pod/build-pod\n  event: ") {
+		t.Errorf("conditions.txt does not attach events to their object:\n%s", conditions)
+	}
+	configMaps, err := os.ReadFile(filepath.Join(dir, "resources", "configmap.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(configMaps), "s3cr3t-value") || !strings.Contains(string(configMaps), redact.Placeholder) {
+		t.Fatalf("config maps not redacted:\n%s", configMaps)
+	}
+}
+
+func TestBundleDirNameDistinguishesAttempts(t *testing.T) {
+	report := types.SpecReport{LeafNodeText: "a/b: c", NumAttempts: 1}
+	first := bundleDirName(report)
+	report.NumAttempts = 2
+	if retry := bundleDirName(report); retry != first+"-attempt-2" {
+		t.Fatalf("bundleDirName() = %q, want %q", retry, first+"-attempt-2")
+	}
+	if strings.ContainsAny(first, "/: ") {
+		t.Fatalf("bundleDirName() = %q contains unsafe characters", first)
+	}
+}
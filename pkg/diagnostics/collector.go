@@ -0,0 +1,527 @@
+package diagnostics
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pacclientset "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/typed/pipelinesascode/v1alpha1"
+	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	pversioned "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	triggersclientset "github.com/tektoncd/triggers/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/k8s"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+// defaultParallelism bounds the concurrent API calls of a Collector.
+const defaultParallelism = 8
+
+// approvalTaskGVR addresses ApprovalTasks through the dynamic client, since
+// clients.Clients only holds an ApprovalTask client for one namespace.
+var approvalTaskGVR = schema.GroupVersionResource{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}
+
+// Collector gathers diagnostics through the Kubernetes, Tekton, Triggers, PAC and
+// operator APIs, so no oc binary is needed. Nil clients are skipped, which keeps
+// a Collector usable against a partially wired or fake clients.Clients.
+type Collector struct {
+	Kube     kubernetes.Interface
+	Dynamic  dynamic.Interface
+	Tekton   pversioned.Interface
+	Triggers triggersclientset.Interface
+	PAC      pacclientset.PipelinesascodeV1alpha1Interface
+	Operator operatorv1alpha1.OperatorV1alpha1Interface
+
+	// Parallelism bounds the concurrent API calls; 0 means defaultParallelism.
+	Parallelism int
+	// MaxEvents keeps the latest events only; 0 keeps all of them.
+	MaxEvents int
+	// MaxPods caps the pods whose logs are collected; 0 collects every pod.
+	MaxPods int
+	// TailLines keeps the last lines of each container log; 0 keeps full logs.
+	TailLines int64
+}
+
+// NewCollector returns a Collector reading full, untruncated diagnostics through c.
+func NewCollector(c *clients.Clients) *Collector {
+	collector := &Collector{
+		Dynamic:  c.Dynamic,
+		Tekton:   c.Tekton,
+		Triggers: c.TriggersClient,
+		PAC:      c.PacClientset,
+		Operator: c.Operator,
+	}
+	if c.KubeClient != nil {
+		collector.Kube = c.KubeClient.Kube
+	}
+	return collector
+}
+
+// Collection is the structured result of a Collector. Render it with Text, YAML
+// or JSON; every rendering is redacted.
+type Collection struct {
+	Namespace   string         `json:"namespace,omitempty"`
+	CollectedAt time.Time      `json:"collectedAt"`
+	Events      []Event        `json:"events,omitempty"`
+	Resources   []Resource     `json:"resources,omitempty"`
+	Logs        []ContainerLog `json:"logs,omitempty"`
+	// Errors lists what could not be collected, e.g. kinds whose CRD is not installed.
+	Errors []string `json:"errors,omitempty"`
+
+	mu sync.Mutex
+}
+
+// Event is a Kubernetes event reduced to what triage needs.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Object  string    `json:"object"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"`
+}
+
+// Resource is one collected object with its conditions summarized.
+type Resource struct {
+	Kind       string         `json:"kind"`
+	Namespace  string         `json:"namespace,omitempty"`
+	Name       string         `json:"name"`
+	Conditions []string       `json:"conditions,omitempty"`
+	Object     map[string]any `json:"object"`
+}
+
+// ContainerLog holds the log of one container, or of its previous instance.
+type ContainerLog struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Previous  bool   `json:"previous,omitempty"`
+	Restarts  int32  `json:"restarts,omitempty"`
+	Log       string `json:"log"`
+	Error     string `json:"error,omitempty"`
+
+	// order is the position of the log in pod and container start order.
+	order int
+}
+
+// lister lists one kind of resource in a namespace ("" for cluster-scoped kinds).
+type lister struct {
+	kind string
+	list func(ctx context.Context, namespace string) (runtime.Object, error)
+}
+
+// namespacedListers returns the listers of the Tekton, Triggers, PAC, approval
+// and core kinds saved for a namespace, skipping nil clients.
+func (c *Collector) namespacedListers() []lister {
+	opts := metav1.ListOptions{}
+	var listers []lister
+	if c.Tekton != nil {
+		listers = append(listers,
+			lister{"PipelineRun", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Tekton.TektonV1().PipelineRuns(ns).List(ctx, opts))
+			}},
+			lister{"TaskRun", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Tekton.TektonV1().TaskRuns(ns).List(ctx, opts))
+			}},
+			lister{"Pipeline", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Tekton.TektonV1().Pipelines(ns).List(ctx, opts))
+			}},
+			lister{"Task", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Tekton.TektonV1().Tasks(ns).List(ctx, opts))
+			}})
+	}
+	if c.Triggers != nil {
+		listers = append(listers,
+			lister{"EventListener", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Triggers.TriggersV1beta1().EventListeners(ns).List(ctx, opts))
+			}},
+			lister{"TriggerBinding", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Triggers.TriggersV1beta1().TriggerBindings(ns).List(ctx, opts))
+			}},
+			lister{"TriggerTemplate", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Triggers.TriggersV1beta1().TriggerTemplates(ns).List(ctx, opts))
+			}})
+	}
+	if c.PAC != nil {
+		listers = append(listers, lister{"Repository", func(ctx context.Context, ns string) (runtime.Object, error) {
+			return objectList(c.PAC.Repositories(ns).List(ctx, opts))
+		}})
+	}
+	if c.Dynamic != nil {
+		listers = append(listers, lister{"ApprovalTask", func(ctx context.Context, ns string) (runtime.Object, error) {
+			return objectList(c.Dynamic.Resource(approvalTaskGVR).Namespace(ns).List(ctx, opts))
+		}})
+	}
+	if c.Kube != nil {
+		listers = append(listers,
+			lister{"Pod", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Kube.CoreV1().Pods(ns).List(ctx, opts))
+			}},
+			lister{"Service", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Kube.CoreV1().Services(ns).List(ctx, opts))
+			}},
+			lister{"ConfigMap", func(ctx context.Context, ns string) (runtime.Object, error) {
+				return objectList(c.Kube.CoreV1().ConfigMaps(ns).List(ctx, opts))
+			}})
+	}
+	return listers
+}
+
+// operatorListers returns the listers of the cluster-scoped operator kinds.
+func (c *Collector) operatorListers() []lister {
+	if c.Operator == nil {
+		return nil
+	}
+	opts := metav1.ListOptions{}
+	return []lister{
+		{"TektonConfig", func(ctx context.Context, _ string) (runtime.Object, error) {
+			return objectList(c.Operator.TektonConfigs().List(ctx, opts))
+		}},
+		{"TektonPipeline", func(ctx context.Context, _ string) (runtime.Object, error) {
+			return objectList(c.Operator.TektonPipelines().List(ctx, opts))
+		}},
+		{"TektonTrigger", func(ctx context.Context, _ string) (runtime.Object, error) {
+			return objectList(c.Operator.TektonTriggers().List(ctx, opts))
+		}},
+		{"TektonChain", func(ctx context.Context, _ string) (runtime.Object, error) {
+			return objectList(c.Operator.TektonChains().List(ctx, opts))
+		}},
+		{"TektonInstallerSet", func(ctx context.Context, _ string) (runtime.Object, error) {
+			return objectList(c.Operator.TektonInstallerSets().List(ctx, opts))
+		}},
+	}
+}
+
+// objectList adapts a typed List call to a lister.
+func objectList[L runtime.Object](list L, err error) (runtime.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CollectNamespace collects the events, Tekton and core resources and container logs
+// of namespace.
+func (c *Collector) CollectNamespace(ctx context.Context, namespace string) *Collection {
+	col := &Collection{Namespace: namespace, CollectedAt: time.Now().UTC()}
+	c.collect(ctx, col, namespace, c.namespacedListers())
+	return col
+}
+
+// CollectOperator collects the TektonConfig, component and installer set status, plus
+// the events, pods and container logs of the operator namespace.
+func (c *Collector) CollectOperator(ctx context.Context, namespace string) *Collection {
+	col := &Collection{Namespace: namespace, CollectedAt: time.Now().UTC()}
+	listers := c.operatorListers()
+	if c.Kube != nil {
+		listers = append(listers, lister{"Pod", func(ctx context.Context, ns string) (runtime.Object, error) {
+			return objectList(c.Kube.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{}))
+		}})
+	}
+	c.collect(ctx, col, namespace, listers)
+	return col
+}
+
+// collect runs the listers, the event listing and, once pods are known, the log
+// collection with at most Parallelism API calls in flight.
+func (c *Collector) collect(ctx context.Context, col *Collection, namespace string, listers []lister) {
+	sem := make(chan struct{}, cmp.Or(c.Parallelism, defaultParallelism))
+	var wg sync.WaitGroup
+	spawn := func(fn func()) {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn()
+		})
+	}
+
+	if c.Kube != nil {
+		spawn(func() { c.collectEvents(ctx, col, namespace) })
+	}
+	for _, l := range listers {
+		spawn(func() {
+			list, err := l.list(ctx, namespace)
+			if err != nil {
+				col.addError("%s: %v", l.kind, err)
+				return
+			}
+			resources, err := toResources(l.kind, list)
+			if err != nil {
+				col.addError("%s: %v", l.kind, err)
+				return
+			}
+			col.mu.Lock()
+			col.Resources = append(col.Resources, resources...)
+			col.mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if c.Kube != nil {
+		pods := col.pods()
+		if c.MaxPods > 0 && len(pods) > c.MaxPods {
+			col.addError("logs collected for %d of %d pods", c.MaxPods, len(pods))
+			pods = pods[:c.MaxPods]
+		}
+		order := 0
+		for _, pod := range pods {
+			for _, ctr := range containersOf(&pod) {
+				for _, previous := range []bool{false, true} {
+					if previous && ctr.restarts == 0 {
+						continue
+					}
+					position := order
+					order++
+					spawn(func() {
+						log := c.containerLog(ctx, pod.Namespace, pod.Name, ctr, previous)
+						log.order = position
+						col.addLog(log)
+					})
+				}
+			}
+		}
+		wg.Wait()
+	}
+	col.sort()
+}
+
+// collectEvents lists the events of namespace, oldest first.
+func (c *Collector) collectEvents(ctx context.Context, col *Collection, namespace string) {
+	list, err := c.Kube.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		col.addError("events: %v", err)
+		return
+	}
+	events := make([]Event, 0, len(list.Items))
+	for _, e := range list.Items {
+		events = append(events, Event{
+			Time:    k8s.EventTime(&e),
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
+			Message: strings.TrimSpace(e.Message),
+			Count:   e.Count,
+		})
+	}
+	slices.SortStableFunc(events, func(a, b Event) int { return a.Time.Compare(b.Time) })
+	if c.MaxEvents > 0 && len(events) > c.MaxEvents {
+		col.addError("kept the latest %d of %d events", c.MaxEvents, len(events))
+		events = events[len(events)-c.MaxEvents:]
+	}
+	col.mu.Lock()
+	col.Events = events
+	col.mu.Unlock()
+}
+
+// container names a container of a pod and how often it restarted.
+type container struct {
+	name     string
+	restarts int32
+}
+
+// containersOf lists the init, regular and ephemeral containers of pod in start
+// order. Tekton step containers are regular containers.
+func containersOf(pod *corev1.Pod) []container {
+	restarts := map[string]int32{}
+	for _, statuses := range [][]corev1.ContainerStatus{
+		pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses,
+	} {
+		for _, status := range statuses {
+			restarts[status.Name] = status.RestartCount
+		}
+	}
+	var containers []container
+	for _, c := range pod.Spec.InitContainers {
+		containers = append(containers, container{c.Name, restarts[c.Name]})
+	}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, container{c.Name, restarts[c.Name]})
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, container{c.Name, restarts[c.Name]})
+	}
+	return containers
+}
+
+// containerLog reads the log of one container, or of its previous instance.
+func (c *Collector) containerLog(ctx context.Context, namespace, pod string, ctr container, previous bool) ContainerLog {
+	opts := &corev1.PodLogOptions{Container: ctr.name, Previous: previous}
+	if c.TailLines > 0 {
+		opts.TailLines = &c.TailLines
+	}
+	log := ContainerLog{Pod: pod, Container: ctr.name, Previous: previous, Restarts: ctr.restarts}
+	data, err := c.Kube.CoreV1().Pods(namespace).GetLogs(pod, opts).DoRaw(ctx)
+	if err != nil {
+		log.Error = err.Error()
+		return log
+	}
+	log.Log = string(data)
+	return log
+}
+
+// toResources converts a typed or unstructured list into Resources.
+func toResources(kind string, list runtime.Object) ([]Resource, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(list)
+	if err != nil {
+		return nil, err
+	}
+	items, _, _ := unstructured.NestedSlice(content, "items")
+	resources := make([]Resource, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		// Typed list items carry no TypeMeta.
+		if _, ok := obj["kind"]; !ok {
+			obj["kind"] = kind
+		}
+		unstructured.RemoveNestedField(obj, "metadata", "managedFields")
+		name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+		namespace, _, _ := unstructured.NestedString(obj, "metadata", "namespace")
+		resources = append(resources, Resource{
+			Kind:       kind,
+			Namespace:  namespace,
+			Name:       name,
+			Conditions: k8s.ConditionLines(obj),
+			Object:     obj,
+		})
+	}
+	return resources, nil
+}
+
+func (col *Collection) addError(format string, a ...any) {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	col.Errors = append(col.Errors, fmt.Sprintf(format, a...))
+}
+
+func (col *Collection) addLog(log ContainerLog) {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	col.Logs = append(col.Logs, log)
+}
+
+// pods returns the collected pods, sorted by name.
+func (col *Collection) pods() []corev1.Pod {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	var pods []corev1.Pod
+	for _, r := range col.Resources {
+		if r.Kind != "Pod" {
+			continue
+		}
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(r.Object, &pod); err == nil {
+			pods = append(pods, pod)
+		}
+	}
+	slices.SortFunc(pods, func(a, b corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	return pods
+}
+
+// sort orders everything collected concurrently, so renderings are stable.
+func (col *Collection) sort() {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	slices.SortFunc(col.Resources, func(a, b Resource) int {
+		return cmp.Or(strings.Compare(a.Kind, b.Kind), strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(col.Logs, func(a, b ContainerLog) int { return a.order - b.order })
+	slices.Sort(col.Errors)
+}
+
+// ResourcesOf returns the collected resources of kind.
+func (col *Collection) ResourcesOf(kind string) []Resource {
+	var resources []Resource
+	for _, r := range col.Resources {
+		if r.Kind == kind {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// Text renders the collection for a Ginkgo report entry.
+func (col *Collection) Text() string {
+	var sb strings.Builder
+	sb.WriteString("\n--- Events ---\n")
+	if len(col.Events) == 0 {
+		sb.WriteString("[no events]\n")
+	}
+	for _, e := range col.Events {
+		sb.WriteString(e.String() + "\n")
+	}
+	sb.WriteString("\n--- Resource State ---\n")
+	for _, r := range col.Resources {
+		fmt.Fprintf(&sb, "%s/%s\n", r.Kind, r.Name)
+		for _, condition := range r.Conditions {
+			fmt.Fprintf(&sb, "  %s\n", condition)
+		}
+	}
+	sb.WriteString("\n--- Pod Logs ---\n")
+	for _, log := range col.Logs {
+		sb.WriteString(log.header() + "\n")
+		if log.Error != "" {
+			fmt.Fprintf(&sb, "[error: %s]\n", log.Error)
+		}
+		sb.WriteString(log.Log)
+		if !strings.HasSuffix(log.Log, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	if len(col.Errors) > 0 {
+		sb.WriteString("\n--- Collection Errors ---\n")
+		sb.WriteString(strings.Join(col.Errors, "\n") + "\n")
+	}
+	return redact.String(sb.String())
+}
+
+// JSON renders the collection as indented JSON.
+func (col *Collection) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(col, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(redact.String(string(data))), nil
+}
+
+// YAML renders the collection as YAML.
+func (col *Collection) YAML() ([]byte, error) {
+	data, err := yaml.Marshal(col)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(redact.String(string(data))), nil
+}
+
+// String renders an event as one line.
+func (e Event) String() string {
+	line := fmt.Sprintf("%s %s %s %s: %s", e.Time.Format(time.RFC3339), e.Type, e.Object, e.Reason, e.Message)
+	if e.Count > 1 {
+		line += fmt.Sprintf(" (x%d)", e.Count)
+	}
+	return line
+}
+
+// header describes the container a log belongs to.
+func (l ContainerLog) header() string {
+	h := fmt.Sprintf("--- Pod: %s  Container: %s", l.Pod, l.Container)
+	if l.Previous {
+		h += fmt.Sprintf(" (previous, %d restarts)", l.Restarts)
+	}
+	return h + " ---"
+}
@@ -0,0 +1,160 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pacfake "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/fake"
+	operatorv1alpha1 "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonfake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	triggersfake "github.com/tektoncd/triggers/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// fakeCollector returns a Collector over fake clientsets holding a failed
+// PipelineRun, its pod with a restarted step, a warning event and a TektonConfig.
+// ApprovalTasks are not installed.
+func fakeCollector() *Collector {
+	now := metav1.NewTime(time.Now())
+	kube := kubefake.NewClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "build-pod", Namespace: "demo"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "prepare"}},
+				Containers:     []corev1.Container{{Name: "step-compile"}},
+			},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "step-compile", RestartCount: 1}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "tekton-operator", Namespace: "openshift-operators"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "operator"}}},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "build-pod.1", Namespace: "demo"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "build-pod"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			LastTimestamp:  now,
+		},
+	)
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{approvalTaskGVR: "ApprovalTaskList"})
+	dynamic.PrependReactor("list", "approvaltasks", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("the server could not find the requested resource")
+	})
+	return &Collector{
+		Kube:    kube,
+		Dynamic: dynamic,
+		Tekton: tektonfake.NewSimpleClientset(&pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "demo"},
+			Status: pipelinev1.PipelineRunStatus{Status: duckv1.Status{Conditions: duckv1.Conditions{{
+				Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed", Message: "Tasks Completed: 1 (Failed: 1)",
+			}}}},
+		}),
+		Triggers: triggersfake.NewSimpleClientset(),
+		PAC:      pacfake.NewSimpleClientset().PipelinesascodeV1alpha1(),
+		Operator: operatorfake.NewSimpleClientset(&operatorv1alpha1.TektonConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		}).OperatorV1alpha1(),
+	}
+}
+
+func TestCollectNamespace(t *testing.T) {
+	col := fakeCollector().CollectNamespace(context.Background(), "demo")
+
+	if len(col.Events) != 1 || col.Events[0].Object != "Pod/build-pod" {
+		t.Fatalf("unexpected events: %+v", col.Events)
+	}
+	prs := col.ResourcesOf("PipelineRun")
+	if len(prs) != 1 || prs[0].Object["kind"] != "PipelineRun" {
+		t.Fatalf("unexpected PipelineRuns: %+v", prs)
+	}
+	if want := "Succeeded=False reason=Failed: Tasks Completed: 1 (Failed: 1)"; len(prs[0].Conditions) != 1 || prs[0].Conditions[0] != want {
+		t.Fatalf("conditions = %v, want [%s]", prs[0].Conditions, want)
+	}
+
+	var logs []string
+	for _, l := range col.Logs {
+		name := l.Pod + "/" + l.Container
+		if l.Previous {
+			name += " (previous)"
+		}
+		logs = append(logs, name)
+	}
+	if got, want := strings.Join(logs, ", "), "build-pod/prepare, build-pod/step-compile, build-pod/step-compile (previous)"; got != want {
+		t.Fatalf("logs = %s, want %s", got, want)
+	}
+	if len(col.Errors) != 1 || !strings.HasPrefix(col.Errors[0], "ApprovalTask: ") {
+		t.Fatalf("errors = %v, want the missing ApprovalTask CRD only", col.Errors)
+	}
+}
+
+func TestCollectOperator(t *testing.T) {
+	col := fakeCollector().CollectOperator(context.Background(), "openshift-operators")
+	if configs := col.ResourcesOf("TektonConfig"); len(configs) != 1 || configs[0].Name != "config" {
+		t.Fatalf("unexpected TektonConfigs: %+v", configs)
+	}
+	if pods := col.ResourcesOf("Pod"); len(pods) != 1 || pods[0].Name != "tekton-operator" {
+		t.Fatalf("unexpected operator pods: %+v", pods)
+	}
+	if len(col.Logs) != 1 || col.Logs[0].Container != "operator" {
+		t.Fatalf("unexpected operator logs: %+v", col.Logs)
+	}
+}
+
+func TestCollectionRenderings(t *testing.T) {
+	c := fakeCollector()
+	c.MaxPods = 1
+	c.Parallelism = 1
+	col := c.CollectNamespace(context.Background(), "demo")
+
+	text := col.Text()
+	for _, want := range []string{
+		"--- Events ---", "Pod/build-pod BackOff: Back-off restarting failed container",
+		"PipelineRun/build\n  Succeeded=False reason=Failed",
+		"--- Pod: build-pod  Container: step-compile (previous, 1 restarts) ---",
+		"--- Collection Errors ---",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() lacks %q:\n%s", want, text)
+		}
+	}
+
+	data, err := col.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Collection
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Namespace != "demo" || len(decoded.Resources) != len(col.Resources) {
+		t.Fatalf("JSON round trip lost data: %s", data)
+	}
+
+	data, err = col.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "namespace: demo") {
+		t.Fatalf("unexpected YAML:\n%s", data)
+	}
+}
@@ -1,63 +1,59 @@
 // Package diagnostics provides cluster diagnostic collection for Ginkgo ReportAfterEach.
 // It collects pod logs, events, and resource state when tests fail, attaching them
-// to the Ginkgo report for CI debuggability without cluster access.
+// to the Ginkgo report for CI debuggability without cluster access. Collection goes
+// through the Kubernetes and Tekton APIs (see Collector), so no oc binary is needed.
 package diagnostics
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"os/exec"
-	"strings"
 	"time"
 
-	testcmd "github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
 )
 
 const (
-	// commandTimeout is the maximum time allowed for each oc command.
-	commandTimeout = 30 * time.Second
+	// collectTimeout bounds the API calls made for one report entry or bundle.
+	collectTimeout = 2 * time.Minute
 
-	// maxEventLines caps the number of event lines collected.
-	maxEventLines = 100
+	// maxEvents caps the number of events in a report entry.
+	maxEvents = 100
 
-	// maxPods caps the number of pods from which logs are collected.
+	// maxPods caps the number of pods whose logs are added to a report entry.
 	maxPods = 10
 
-	// maxLogLines caps the number of log lines per container.
+	// maxLogLines caps the number of log lines per container in a report entry.
 	maxLogLines = 50
 )
 
 // CollectOperatorLogsOnFailure returns a ReportAfterEach function that captures
-// pod logs and events from the openshift-operators namespace on test failure.
-// This is the namespace where the Tekton operator controller, proxy-webhook and
-// OLM CSV pods run — the most useful source of information when the install or
-// webhook steps fail in CI.
+// operator status, pod logs and events from the openshift-operators namespace on
+// test failure. This is the namespace where the Tekton operator controller,
+// proxy-webhook and OLM CSV pods run — the most useful source of information when
+// the install or webhook steps fail in CI.
 //
 // Usage:
 //
-//	var _ = ReportAfterEach(diagnostics.CollectOperatorLogsOnFailure())
-func CollectOperatorLogsOnFailure() func(SpecReport) {
+//	var _ = ReportAfterEach(diagnostics.CollectOperatorLogsOnFailure(func() *clients.Clients { return sharedClients }))
+func CollectOperatorLogsOnFailure(clientsFunc func() *clients.Clients) func(SpecReport) {
 	return func(report SpecReport) {
-		if !report.Failed() {
+		cs := clientsFunc()
+		if !report.Failed() || cs == nil {
 			return
 		}
 		const ns = "openshift-operators"
-		var sb strings.Builder
-		sb.WriteString("\n=== Operator Diagnostics (" + ns + ") ===\n")
-		sb.WriteString("\n--- Events ---\n")
-		sb.WriteString(collectEvents(ns))
-		sb.WriteString("\n--- Pod Logs (operator/webhook pods) ---\n")
-		sb.WriteString(collectPodLogs(ns))
-		AddReportEntry("operator-diagnostics", sb.String())
+		ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+		defer cancel()
+		col := reportCollector(cs).CollectOperator(ctx, ns)
+		AddReportEntry("operator-diagnostics", "\n=== Operator Diagnostics ("+ns+") ===\n"+col.Text())
 	}
 }
 
 // CollectOnFailure returns a function suitable for use with ReportAfterEach.
-// It collects cluster diagnostics (events, resource state, pod logs) only when
-// a spec fails, panics, times out, or is interrupted.
+// It collects cluster diagnostics (events, resource state, pod logs) through the
+// Kubernetes and Tekton APIs only when a spec fails, panics, times out, or is
+// interrupted.
 //
 // The namespace parameter is a pointer so that the current namespace value is
 // read at report time (not at registration time), supporting per-spec namespaces.
@@ -65,8 +61,8 @@ func CollectOperatorLogsOnFailure() func(SpecReport) {
 // Usage:
 //
 //	var lastNamespace string
-//	var _ = ReportAfterEach(diagnostics.CollectOnFailure(&lastNamespace))
-func CollectOnFailure(namespace *string) func(SpecReport) {
+//	var _ = ReportAfterEach(diagnostics.CollectOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+func CollectOnFailure(namespace *string, clientsFunc func() *clients.Clients) func(SpecReport) {
 	return func(report SpecReport) {
 		if !report.Failed() {
 			return
@@ -74,108 +70,24 @@ func CollectOnFailure(namespace *string) func(SpecReport) {
 		if namespace == nil || *namespace == "" {
 			return
 		}
-		ns := *namespace
-		var sb strings.Builder
-		sb.WriteString("\n=== Diagnostics for namespace: " + ns + " ===\n")
-		sb.WriteString("\n--- Events ---\n")
-		sb.WriteString(collectEvents(ns))
-		sb.WriteString("\n--- Resource State ---\n")
-		sb.WriteString(collectResourceState(ns))
-		sb.WriteString("\n--- Pod Logs ---\n")
-		sb.WriteString(collectPodLogs(ns))
-		AddReportEntry("cluster-diagnostics", sb.String())
-	}
-}
-
-// collectEvents runs oc get events sorted by timestamp and caps output.
-func collectEvents(namespace string) string {
-	out, err := runOC("get", "events", "-n", namespace, "--sort-by=.lastTimestamp")
-	if err != nil {
-		return fmt.Sprintf("[error collecting events: %v]\n%s", err, out)
-	}
-	return capLines(out, maxEventLines)
-}
-
-// collectResourceState runs oc get for pipeline/task resources and pods.
-func collectResourceState(namespace string) string {
-	out, err := runOC("get", "pipelineruns,taskruns,pods", "-n", namespace, "-o", "wide")
-	if err != nil {
-		return fmt.Sprintf("[error collecting resource state: %v]\n%s", err, out)
-	}
-	return out
-}
-
-// collectPodLogs collects the last N lines of logs from each container in each pod,
-// limited to maxPods pods.
-func collectPodLogs(namespace string) string {
-	// List pod names
-	podListOut, err := runOC("get", "pods", "-n", namespace, "-o", "jsonpath={.items[*].metadata.name}")
-	if err != nil {
-		return fmt.Sprintf("[error listing pods: %v]\n%s", err, podListOut)
-	}
-
-	podNames := strings.Fields(strings.TrimSpace(podListOut))
-	if len(podNames) == 0 {
-		return "[no pods found in namespace]"
-	}
-
-	// Cap the number of pods
-	if len(podNames) > maxPods {
-		podNames = podNames[:maxPods]
-	}
-
-	var sb strings.Builder
-	for _, pod := range podNames {
-		// Get container names for this pod
-		containerOut, err := runOC("get", "pod", pod, "-n", namespace,
-			"-o", "jsonpath={.spec.containers[*].name}")
-		if err != nil {
-			fmt.Fprintf(&sb, "--- Pod: %s [error getting containers: %v] ---\n", pod, err)
-			continue
-		}
-
-		containers := strings.Fields(strings.TrimSpace(containerOut))
-		for _, container := range containers {
-			fmt.Fprintf(&sb, "--- Pod: %s  Container: %s (last %d lines) ---\n",
-				pod, container, maxLogLines)
-
-			logOut, err := runOC("logs", "-n", namespace, pod, "-c", container,
-				fmt.Sprintf("--tail=%d", maxLogLines))
-			if err != nil {
-				fmt.Fprintf(&sb, "[error: %v]\n", err)
-			} else {
-				sb.WriteString(logOut)
-			}
-			sb.WriteString("\n")
+		cs := clientsFunc()
+		if cs == nil {
+			return
 		}
+		ns := *namespace
+		ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+		defer cancel()
+		col := reportCollector(cs).CollectNamespace(ctx, ns)
+		AddReportEntry("cluster-diagnostics", "\n=== Diagnostics for namespace: "+ns+" ===\n"+col.Text())
 	}
-	return sb.String()
-}
-
-// runOC executes oc with shared connection flags and returns command errors to the reporter.
-func runOC(args ...string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
-	defer cancel()
-
-	commandArgs := testcmd.Command(append([]string{"oc"}, args...)...)
-	cmd := exec.CommandContext(ctx, commandArgs[0], commandArgs[1:]...) //nolint:gosec // G204: subprocess args are controlled by test code
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		return stdout.String() + stderr.String(), fmt.Errorf("oc %s: %w", strings.Join(args, " "), err)
-	}
-	return stdout.String(), nil
 }
 
-// capLines truncates output to maxLines, adding a truncation notice if needed.
-func capLines(output string, maxLines int) string {
-	lines := strings.Split(output, "\n")
-	if len(lines) <= maxLines {
-		return output
-	}
-	truncated := strings.Join(lines[:maxLines], "\n")
-	return truncated + fmt.Sprintf("\n... [truncated: showing %d of %d lines]", maxLines, len(lines))
+// reportCollector returns a Collector capped for report entries, which are
+// printed inline; failure bundles keep everything.
+func reportCollector(cs *clients.Clients) *Collector {
+	c := NewCollector(cs)
+	c.MaxEvents = maxEvents
+	c.MaxPods = maxPods
+	c.TailLines = maxLogLines
+	return c
 }
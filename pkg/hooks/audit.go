@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+)
+
+// AuditCommands records every CLI invocation made through pkg/cmd (and so pkg/oc,
+// pkg/opc and pkg/diagnostics) while a spec runs, and attaches the log to the spec
+// as a "command-audit" report entry of JSON lines. The entry is printed on failure
+// or with -v, and always lands in --json-report/--junit-report output.
+//
+// Register it last in suite_test.go so namespace setup from earlier BeforeEach
+// hooks stays out of the log and commands run by earlier ReportAfterEach
+// collectors, such as diagnostics.WriteBundleOnFailure, are included. SuiteHooks
+// registers it after its other hooks:
+//
+//	var _ = ReportAfterEach(diagnostics.WriteBundleOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+//	var _ = hooks.SuiteHooks()
+func AuditCommands() bool {
+	BeforeEach(func() {
+		cmd.StartAudit()
+	})
+
+	ReportAfterEach(func(_ SpecReport) {
+		if log := cmd.StopAudit(); log != "" {
+			AddReportEntry("command-audit", log, ReportEntryVisibilityFailureOrVerbose)
+		}
+	})
+
+	return true
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/diagnostics"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/operator"
+)
+
+// healthSnapshotTimeout bounds each operator health snapshot.
+const healthSnapshotTimeout = 2 * time.Minute
+
+// SuiteHooks registers the hooks the cluster suites share, in order:
+// OperatorHealthDiff, ScopedStore and AuditCommands. Suites that install,
+// upgrade or remove the operator register ScopedStore and AuditCommands on
+// their own instead:
+//
+//	var _ = hooks.SuiteHooks()
+func SuiteHooks() bool {
+	OperatorHealthDiff()
+	ScopedStore()
+	return AuditCommands()
+}
+
+// OperatorHealthDiff snapshots the operator-managed cluster state (see
+// operator.TakeHealthSnapshot) before the suite's first spec and again once every
+// parallel node has finished, and reports what changed. The diff is
+// written to GinkgoWriter, added to the suite report as an "operator health
+// diff" entry and, when ARTIFACTS_DIR is set, written to
+// operator-health-<suite>.txt there, so specs that leave the pruner, TLS profile
+// or chains configuration modified are caught by the suite that did it. With
+// --fail-on-operator-drift a non-empty diff also fails the suite.
+//
+// Suites that install, upgrade or remove the operator should not register it.
+// The snapshots use their own clients, built from the connection flags, since
+// they run before SynchronizedBeforeSuite creates the suite's clients.
+func OperatorHealthDiff() bool {
+	var before *operator.HealthSnapshot
+
+	ReportBeforeSuite(func(ctx SpecContext, _ Report) {
+		cs, err := healthClients()
+		if err != nil {
+			log.Printf("operator health: skipping baseline snapshot: %v", err)
+			return
+		}
+		before = operator.TakeHealthSnapshot(ctx, cs)
+	}, NodeTimeout(healthSnapshotTimeout))
+
+	ReportAfterSuite("operator health diff", func(ctx SpecContext, report Report) {
+		if before == nil {
+			return
+		}
+		cs, err := healthClients()
+		if err != nil {
+			log.Printf("operator health: skipping final snapshot: %v", err)
+			return
+		}
+		after := operator.TakeHealthSnapshot(ctx, cs)
+		diff := operator.DiffHealth(before, after)
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Operator health changes during %q (%s to %s):\n%s\n",
+			report.SuiteDescription, before.TakenAt.Format(time.RFC3339), after.TakenAt.Format(time.RFC3339), diff)
+		if len(after.Errors) > 0 {
+			fmt.Fprintf(&sb, "Not compared:\n%s\n", strings.Join(after.Errors, "\n"))
+		}
+		fmt.Fprint(GinkgoWriter, sb.String())
+		if len(diff) > 0 {
+			AddReportEntry("operator health diff", sb.String())
+		}
+
+		if root := os.Getenv(diagnostics.ArtifactsDirEnv); root != "" {
+			name := "operator-health-" + suiteFileName(report.SuiteDescription) + ".txt"
+			if err := os.WriteFile(filepath.Join(root, name), []byte(sb.String()), 0o600); err != nil {
+				log.Printf("operator health: failed to write %s: %v", name, err)
+			}
+		}
+		if len(diff) > 0 && config.Flags.FailOnOperatorDrift {
+			Fail(sb.String())
+		}
+	}, NodeTimeout(healthSnapshotTimeout))
+
+	return true
+}
+
+// healthClients builds clients from the connection flags.
+func healthClients() (*clients.Clients, error) {
+	return clients.NewClientsWithContext(config.Flags.Kubeconfig, config.Flags.Cluster, config.Flags.Context, config.TargetNamespace)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// suiteFileName turns "Pipelines Suite" into "pipelines-suite".
+func suiteFileName(description string) string {
+	return strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(description), "-"), "-")
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
+	"github.com/onsi/ginkgo/v2/types"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+)
+
+// ScopedStore drives the lifecycle of typed store keys: spec-scoped values are
+// cleared before every spec, container-scoped values when the specs of the next
+// container start. A container scope is the spec's outermost Ordered container,
+// so its nested Contexts share values, or its innermost container when it is not
+// in an Ordered one. When a spec fails, everything it could see in the store is
+// attached to the report as a "store" entry.
+//
+// Usage in suite_test.go:
+//
+//	var _ = hooks.ScopedStore()
+func ScopedStore() bool {
+	BeforeEach(func() {
+		report := CurrentSpecReport()
+		store.EnterSpec(containerScope(report.ContainerHierarchyLocations, report.IsInOrderedContainer))
+	})
+
+	ReportAfterEach(func(report SpecReport) {
+		if !report.Failed() {
+			return
+		}
+		if dump := store.Dump(); dump != "" {
+			AddReportEntry("store", dump, ReportEntryVisibilityFailureOrVerbose)
+		}
+	})
+
+	return true
+}
+
+// containerScope identifies the container scope of a spec by the code location
+// of its outermost Ordered container. Ginkgo reports only whether some container
+// is Ordered, so each container's call is looked up in the spec source; when the
+// source cannot be read the top-level container stands in. Specs outside an
+// Ordered container are scoped to their innermost container.
+func containerScope(containers []types.CodeLocation, inOrdered bool) string {
+	if len(containers) == 0 {
+		return ""
+	}
+	if !inOrdered {
+		return containers[len(containers)-1].String()
+	}
+	for _, loc := range containers {
+		if isOrdered(loc) {
+			return loc.String()
+		}
+	}
+	return containers[0].String()
+}
+
+// orderedContainers caches isOrdered by code location.
+var orderedContainers sync.Map
+
+// isOrdered reports whether the container call at loc passes the Ordered decorator.
+func isOrdered(loc types.CodeLocation) bool {
+	if ordered, ok := orderedContainers.Load(loc.String()); ok {
+		return ordered.(bool)
+	}
+	ordered := callHasOrdered(loc.FileName, loc.LineNumber)
+	orderedContainers.Store(loc.String(), ordered)
+	return ordered
+}
+
+// callHasOrdered parses file and reports whether a call starting on line has an
+// Ordered argument, as in Describe("...", Ordered, func() {...}).
+func callHasOrdered(file string, line int) bool {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return false
+	}
+	ordered := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if ordered || !ok || fset.Position(call.Pos()).Line != line {
+			return !ordered
+		}
+		for _, arg := range call.Args {
+			switch arg := arg.(type) {
+			case *ast.Ident:
+				ordered = ordered || arg.Name == "Ordered"
+			case *ast.SelectorExpr:
+				ordered = ordered || arg.Sel.Name == "Ordered"
+			}
+		}
+		return !ordered
+	})
+	return ordered
+}
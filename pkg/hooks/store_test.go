@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+)
+
+const specSource = `package demo_test
+
+var _ = Describe("Manual Approval Gate", Ordered, func() {
+	Context("Approve", func() {
+		It("first", func() {})
+	})
+	Context("Reject", func() {
+		It("second", func() {})
+	})
+})
+
+var _ = Describe("auto-prune", Serial,
+	Label("e2e"), func() {
+		Context("TC01", Ordered, ContinueOnFailure, func() {
+			Context("nested", func() {
+				It("first", func() {})
+			})
+		})
+		Context("TC02", ginkgo.Ordered, func() {
+			It("second", func() {})
+		})
+	})
+`
+
+// locations are the container locations of a spec in file, outermost first.
+func locations(file string, lines ...int) []types.CodeLocation {
+	var locs []types.CodeLocation
+	for _, line := range lines {
+		locs = append(locs, types.CodeLocation{FileName: file, LineNumber: line})
+	}
+	return locs
+}
+
+func TestContainerScope(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "demo_test.go")
+	if err := os.WriteFile(file, []byte(specSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(t.TempDir(), "demo_test.go")
+	if err := os.WriteFile(other, []byte(specSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	gate := types.CodeLocation{FileName: file, LineNumber: 3}.String()
+	tc01 := types.CodeLocation{FileName: file, LineNumber: 14}.String()
+
+	tests := []struct {
+		name       string
+		containers []types.CodeLocation
+		inOrdered  bool
+		want       string
+	}{
+		{name: "nested Context of a top-level Ordered Describe", containers: locations(file, 3, 4), inOrdered: true, want: gate},
+		{name: "sibling Context of the same Ordered Describe", containers: locations(file, 3, 7), inOrdered: true, want: gate},
+		{name: "Ordered Context with decorators on a continuation line", containers: locations(file, 12, 14, 15), inOrdered: true, want: tc01},
+		{name: "Ordered Context using a qualified decorator", containers: locations(file, 12, 19), inOrdered: true, want: types.CodeLocation{FileName: file, LineNumber: 19}.String()},
+		{name: "same Describe in another file", containers: locations(other, 3, 4), inOrdered: true, want: types.CodeLocation{FileName: other, LineNumber: 3}.String()},
+		{name: "not Ordered", containers: locations(file, 12), want: types.CodeLocation{FileName: file, LineNumber: 12}.String()},
+		{name: "unreadable source", containers: locations("missing_test.go", 3, 4), inOrdered: true, want: "missing_test.go:3"},
+		{name: "no container"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerScope(tt.containers, tt.inOrdered); got != tt.want {
+				t.Fatalf("containerScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerScopeSharesValuesAcrossNestedContexts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "demo_test.go")
+	if err := os.WriteFile(file, []byte(specSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	key := store.NewKey[string]("approval", store.ScopeContainer)
+	enter := func(lines ...int) {
+		store.EnterSpec(containerScope(locations(file, lines...), true))
+	}
+
+	enter(3, 4)
+	key.Set("pending")
+	enter(3, 7)
+	if got := key.GetOr("none"); got != "pending" {
+		t.Fatalf("value = %q in a sibling Context, want it shared within the Ordered Describe", got)
+	}
+	enter(12, 14, 15)
+	if _, ok := key.Lookup(); ok {
+		t.Fatal("value leaked into the next Ordered container")
+	}
+	key.Set("tc01")
+	enter(12, 19)
+	if _, ok := key.Lookup(); ok {
+		t.Fatal("value leaked into a sibling Ordered Context under the same Describe")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -106,16 +107,87 @@ func ListOptionsDefault() metav1.ListOptions {
 // GetWarningEvents returns warning events from the specified namespace as a single string.
 func GetWarningEvents(c *clients.Clients, namespace string) (string, error) {
 	var eventSlice []string
-	events, err := c.KubeClient.Kube.CoreV1().Events(namespace).List(c.Ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	events, err := listWarningEvents(c.Ctx, c, namespace)
 	if err != nil {
 		return "", err
 	}
-	for _, item := range events.Items {
+	for _, item := range events {
 		eventSlice = append(eventSlice, item.Message)
 	}
 	return strings.Join(eventSlice, "\n"), nil
 }
 
+// GetWarningEventsFor returns the warning events in namespace whose involved object
+// is one of names, oldest first.
+func GetWarningEventsFor(ctx context.Context, c *clients.Clients, namespace string, names ...string) ([]corev1.Event, error) {
+	events, err := listWarningEvents(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var matched []corev1.Event
+	for _, event := range events {
+		if slices.Contains(names, event.InvolvedObject.Name) {
+			matched = append(matched, event)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b corev1.Event) int {
+		return EventTime(&a).Compare(EventTime(&b))
+	})
+	return matched, nil
+}
+
+// listWarningEvents lists the warning events in namespace.
+func listWarningEvents(ctx context.Context, c *clients.Clients, namespace string) ([]corev1.Event, error) {
+	events, err := c.KubeClient.Kube.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+// EventTime returns when an event was last seen, falling back to its event time
+// and then its creation time for events that never repeated.
+func EventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+// FormatCondition renders a condition as "Type=Status reason=Reason: message",
+// with the message on a single line.
+func FormatCondition(conditionType, status, reason, message string) string {
+	line := conditionType + "=" + status
+	if reason != "" {
+		line += " reason=" + reason
+	}
+	if message = strings.Join(strings.Fields(message), " "); message != "" {
+		line += ": " + message
+	}
+	return line
+}
+
+// ConditionLines renders status.conditions of an unstructured object with
+// FormatCondition, one condition per line.
+func ConditionLines(obj map[string]any) []string {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	lines := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		lines = append(lines, FormatCondition(fmt.Sprint(condition["type"]), fmt.Sprint(condition["status"]), reason, message))
+	}
+	return lines
+}
+
 // Watch func helps you to watch on dynamic resources
 func Watch(ctx context.Context, gr schema.GroupVersionResource, clients *clients.Clients, ns string, op metav1.ListOptions) (watch.Interface, error) {
 	gvr, err := GetGroupVersionResource(gr, clients.Tekton.Discovery())
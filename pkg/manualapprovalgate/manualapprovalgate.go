@@ -41,6 +41,7 @@ import (
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
 )
 
 // ApprovalTaskInfo holds summary information about a Manual Approval Gate task.
@@ -188,6 +189,7 @@ func popUserAuthDirty(user string) bool {
 func userPassword(user string) string {
 	envVar := strings.ToUpper(user) + "_PASS"
 	if v := strings.TrimSpace(os.Getenv(envVar)); v != "" {
+		redact.Register(v)
 		return v
 	}
 	return user
@@ -2,20 +2,27 @@
 package oc
 
 import (
-	"encoding/json"
+	"cmp"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"gotest.tools/v3/icmd"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
 	. "github.com/onsi/gomega"    //nolint:revive,staticcheck // dot import is idiomatic for Gomega
@@ -24,6 +31,16 @@ import (
 // OC struct holds  params which can be used to customize the oc commands.
 type OC struct {
 	Context string
+	// ctx, when set, bounds every command run through this OC.
+	ctx context.Context
+}
+
+// WithContext returns a copy of oc whose commands are killed once ctx is done.
+// Pass a Ginkgo SpecContext so oc does not outlive an interrupted spec.
+func (oc *OC) WithContext(ctx context.Context) *OC {
+	c := *oc
+	c.ctx = ctx
+	return &c
 }
 
 // Create creates resources from a local file using oc command.
@@ -164,20 +181,19 @@ func (oc *OC) VerifyKubernetesEventsForEventListener(namespace string) {
 	Expect(all).To(BeTrue(), "No events for successful, done and started")
 }
 
-// UpdateTektonConfig patches the TektonConfig CR with the provided JSON patch data.
-func (oc *OC) UpdateTektonConfig(patchData string) {
-	oc.runWithLog("patch", "tektonconfig", "config", "-p", patchData, "--type=merge")
+// PatchTektonConfig checks patch against the TektonConfig CRD schema and sends
+// the changes it makes to the live TektonConfig as a merge patch through the
+// API, see tektonconfig.Patch.Apply.
+func (oc *OC) PatchTektonConfig(patch *tektonconfig.Patch) {
+	_, err := patch.Apply(oc.requestContext(), oc.tektonConfigs(), tektonconfig.DefaultName)
+	Expect(err).NotTo(HaveOccurred(), "failed to patch TektonConfig")
 }
 
-// UpdateTektonConfigwithInvalidData patches TektonConfig with invalid data and asserts the expected error message.
-func (oc *OC) UpdateTektonConfigwithInvalidData(patchData, errorMessage string) {
-	result := oc.runIgnoreErrors("patch", "tektonconfig", "config", "-p", patchData, "--type=merge")
-	log.Printf("Output: %s\n", result.Stdout())
-	Expect(result.ExitCode).To(Equal(1),
-		"Expected exit code 1 but got %d", result.ExitCode)
-
-	Expect(result.Stderr()).To(ContainSubstring(errorMessage),
-		"Expected stderr to contain %q but got %q", errorMessage, result.Stderr())
+// PatchTektonConfigExpectError sends patch without checking it first and asserts
+// that the cluster rejects it with errorMessage.
+func (oc *OC) PatchTektonConfigExpectError(patch *tektonconfig.Patch, errorMessage string) {
+	_, err := patch.Unchecked().Apply(oc.requestContext(), oc.tektonConfigs(), tektonconfig.DefaultName)
+	Expect(err).To(MatchError(ContainSubstring(errorMessage)), "expected the TektonConfig patch to be rejected")
 }
 
 // AnnotateNamespace annotates the given namespace with the provided annotation.
@@ -192,120 +208,123 @@ func (oc *OC) AnnotateNamespaceIgnoreErrors(namespace, annotation string) {
 
 // RemovePrunerConfig removes the pruner spec from TektonConfig.
 func (oc *OC) RemovePrunerConfig() {
-	oc.run("patch", "tektonconfig", "config", "-p", "[{ \"op\": \"remove\", \"path\": \"/spec/pruner\" }]", "--type=json")
+	oc.PatchTektonConfig(tektonconfig.NewPatch().Remove("pruner"))
 }
 
 // UpdateAddonConfig patches spec.addon.params for resolverTasks and pipelineTemplates.
 // If expectedMessage is non-empty the patch is expected to be rejected with that error.
 func (oc *OC) UpdateAddonConfig(resolverTasks, pipelineTemplates, expectedMessage string) {
-	patchData := fmt.Sprintf(
-		`{"spec":{"addon":{"params":[{"name":"resolverTasks","value":"%s"},{"name":"pipelineTemplates","value":"%s"}]}}}`,
-		resolverTasks, pipelineTemplates,
-	)
+	patch := tektonconfig.NewPatch().
+		AddonParam("resolverTasks", resolverTasks).
+		AddonParam("pipelineTemplates", pipelineTemplates)
 	if expectedMessage == "" {
-		oc.UpdateTektonConfig(patchData)
+		oc.PatchTektonConfig(patch)
 	} else {
-		oc.UpdateTektonConfigwithInvalidData(patchData, expectedMessage)
+		oc.PatchTektonConfigExpectError(patch, expectedMessage)
 	}
 }
 
 // UpdateTektonConfigParam patches a single spec.params entry by name.
 func (oc *OC) UpdateTektonConfigParam(paramName, value string) {
-	patchData := fmt.Sprintf(`{"spec":{"params":[{"name":"%s","value":"%s"}]}}`, paramName, value)
 	log.Printf("Patching TektonConfig param %s=%s\n", paramName, value)
-	oc.UpdateTektonConfig(patchData)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().Param(paramName, value))
 }
 
 // UpdatePrunerConfig patches spec.pruner with the given schedule, resources, and optional keep/keep-since.
 func (oc *OC) UpdatePrunerConfig(keep, schedule, resources, keepSince string, withKeep, withKeepSince bool) {
-	patch := buildPrunerPatch(keep, schedule, resources, keepSince, withKeep, withKeepSince)
-	log.Printf("Patching TektonConfig pruner: %s\n", patch)
-	oc.UpdateTektonConfig(patch)
+	log.Printf("Patching TektonConfig pruner: schedule=%q resources=%q keep=%q keep-since=%q\n", schedule, resources, keep, keepSince)
+	patch, err := prunerPatch(keep, schedule, resources, keepSince, withKeep, withKeepSince)
+	Expect(err).NotTo(HaveOccurred())
+	oc.PatchTektonConfig(patch)
 }
 
-// UpdatePrunerConfigExpectError patches spec.pruner with invalid data and returns the stderr output.
+// UpdatePrunerConfigExpectError patches spec.pruner with invalid data and returns
+// the error the cluster rejected it with, or "" when it was accepted.
 func (oc *OC) UpdatePrunerConfigExpectError(keep, schedule, resources, keepSince string, withKeep, withKeepSince bool) string {
-	patch := buildPrunerPatch(keep, schedule, resources, keepSince, withKeep, withKeepSince)
-	log.Printf("Patching TektonConfig pruner with invalid data: %s\n", patch)
-	result := oc.runIgnoreErrors("patch", "tektonconfig", "config", "-p", patch, "--type=merge")
-	return result.Stderr()
-}
-
-// buildPrunerPatch constructs the JSON patch string for spec.pruner.
-func buildPrunerPatch(keep, schedule, resources, keepSince string, withKeep, withKeepSince bool) string {
-	fields := []string{fmt.Sprintf(`"schedule":"%s"`, schedule)}
-	resList := strings.Split(resources, ",")
-	resJSON := make([]string, len(resList))
-	for i, r := range resList {
-		resJSON[i] = fmt.Sprintf(`"%s"`, strings.TrimSpace(r))
+	patch, err := prunerPatch(keep, schedule, resources, keepSince, withKeep, withKeepSince)
+	Expect(err).NotTo(HaveOccurred())
+	log.Printf("Patching TektonConfig pruner with invalid data: schedule=%q resources=%q keep=%q keep-since=%q\n", schedule, resources, keep, keepSince)
+	if _, err := patch.Unchecked().Apply(oc.requestContext(), oc.tektonConfigs(), tektonconfig.DefaultName); err != nil {
+		return err.Error()
 	}
-	fields = append(fields, fmt.Sprintf(`"resources":[%s]`, strings.Join(resJSON, ",")))
+	return ""
+}
+
+// prunerPatch sets spec.pruner; keep and keep-since are cleared unless enabled.
+func prunerPatch(keep, schedule, resources, keepSince string, withKeep, withKeepSince bool) (*tektonconfig.Patch, error) {
+	var keepValue, keepSinceValue *uint
+	var err error
 	if withKeep {
-		fields = append(fields, fmt.Sprintf(`"keep":%s`, keep))
-	} else {
-		fields = append(fields, `"keep":null`)
+		if keepValue, err = parseUint("keep", keep); err != nil {
+			return nil, err
+		}
 	}
 	if withKeepSince {
-		fields = append(fields, fmt.Sprintf(`"keep-since":%s`, keepSince))
-	} else {
-		fields = append(fields, `"keep-since":null`)
+		if keepSinceValue, err = parseUint("keep-since", keepSince); err != nil {
+			return nil, err
+		}
 	}
-	return fmt.Sprintf(`{"spec":{"pruner":{%s}}}`, strings.Join(fields, ","))
+	var resList []string
+	for r := range strings.SplitSeq(resources, ",") {
+		resList = append(resList, strings.TrimSpace(r))
+	}
+	return tektonconfig.NewPatch().Pruner(func(p *v1alpha1.Prune) {
+		p.Schedule = schedule
+		p.Resources = resList
+		p.Keep = keepValue
+		p.KeepSince = keepSinceValue
+	}), nil
+}
+
+func parseUint(field, value string) (*uint, error) {
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pruner %s %q: %w", field, value, err)
+	}
+	return new(uint(n)), nil
 }
 
 // EnableLegacyPruner sets spec.pruner.disabled=false in TektonConfig.
 func (oc *OC) EnableLegacyPruner() {
-	oc.UpdateTektonConfig(`{"spec":{"pruner":{"disabled":false}}}`)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().Pruner(func(p *v1alpha1.Prune) { p.Disabled = false }))
 }
 
 // DisableLegacyPruner sets spec.pruner.disabled=true in TektonConfig.
 func (oc *OC) DisableLegacyPruner() {
-	oc.UpdateTektonConfig(`{"spec":{"pruner":{"disabled":true}}}`)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().Pruner(func(p *v1alpha1.Prune) { p.Disabled = true }))
 }
 
 // EnableTektonPruner sets spec.tektonpruner.disabled=false in TektonConfig.
 func (oc *OC) EnableTektonPruner() {
-	oc.UpdateTektonConfig(`{"spec":{"tektonpruner":{"disabled":false}}}`)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().TektonPruner(func(p *v1alpha1.Pruner) { p.Disabled = new(false) }))
 }
 
 // DisableTektonPruner sets spec.tektonpruner.disabled=true in TektonConfig.
 func (oc *OC) DisableTektonPruner() {
-	oc.UpdateTektonConfig(`{"spec":{"tektonpruner":{"disabled":true}}}`)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().TektonPruner(func(p *v1alpha1.Pruner) { p.Disabled = new(true) }))
 }
 
 // SetTektonPrunerGlobalConfig patches spec.tektonpruner.global-config.{param} = value.
-// Dot-notation params (e.g. "namespaces.dev.ttlSecondsAfterFinished") are expanded to
-// nested JSON. If expectedMessage is non-empty the patch is expected to be rejected
-// with that error substring; otherwise the patch must succeed.
+// Dot-notation params (e.g. "namespaces.dev.ttlSecondsAfterFinished") address nested
+// fields. Integer values are sent as numbers, empty or "null" values clear the field.
+// If expectedMessage is non-empty the patch is expected to be rejected with that error
+// substring; otherwise the patch must succeed.
 func (oc *OC) SetTektonPrunerGlobalConfig(param, value, expectedMessage string) {
 	value = strings.TrimSpace(value)
-	var valuePart string
+	var v any = value
 	if value == "" || strings.EqualFold(value, "null") {
-		valuePart = "null"
-	} else if _, err := strconv.Atoi(value); err == nil {
-		valuePart = value
-	} else {
-		valuePart = fmt.Sprintf("\"%s\"", strings.ReplaceAll(value, "\"", "\\\""))
-	}
-
-	var globalConfigJSON string
-	if strings.Contains(param, ".") {
-		parts := strings.Split(param, ".")
-		inner := fmt.Sprintf("\"%s\":%s", parts[len(parts)-1], valuePart)
-		for i := len(parts) - 2; i >= 0; i-- {
-			inner = fmt.Sprintf("\"%s\":{%s}", parts[i], inner)
-		}
-		globalConfigJSON = inner
-	} else {
-		globalConfigJSON = fmt.Sprintf("\"%s\":%s", param, valuePart)
+		v = nil
+	} else if n, err := strconv.Atoi(value); err == nil {
+		v = n
 	}
-	patchData := fmt.Sprintf("{\"spec\":{\"tektonpruner\":{\"global-config\":{%s}}}}", globalConfigJSON)
-	log.Printf("Patching TektonConfig tektonpruner global-config: %s\n", patchData)
+	fields := append([]string{"tektonpruner", "global-config"}, strings.Split(param, ".")...)
+	patch := tektonconfig.NewPatch().Set(v, fields...)
+	log.Printf("Patching TektonConfig tektonpruner global-config: %s=%v\n", param, v)
 
 	if expectedMessage == "" {
-		oc.UpdateTektonConfig(patchData)
+		oc.PatchTektonConfig(patch)
 	} else {
-		oc.UpdateTektonConfigwithInvalidData(patchData, expectedMessage)
+		oc.PatchTektonConfigExpectError(patch, expectedMessage)
 	}
 }
 
@@ -342,30 +361,32 @@ func (oc *OC) SecretExists(secretName, namespace string) bool {
 
 // CreateSecretForGitResolver creates the github-auth-secret used by the git resolver.
 func (oc *OC) CreateSecretForGitResolver(secretData string) {
+	redact.Register(secretData)
 	oc.run("create", "secret", "generic", "github-auth-secret", "--from-literal", "github-auth-key="+secretData, "-n", "openshift-pipelines")
 }
 
 // CreateSecretForWebhook creates the gitlab-webhook-config secret in the given namespace.
 func (oc *OC) CreateSecretForWebhook(tokenSecretData, webhookSecretData, namespace string) {
+	redact.Register(tokenSecretData, webhookSecretData)
 	oc.run("create", "secret", "generic", "gitlab-webhook-config", "--from-literal", "provider.token="+tokenSecretData, "--from-literal", "webhook.secret="+webhookSecretData, "-n", namespace)
 }
 
 // EnableConsolePlugin enables the Pipelines console plugin in the cluster console.
 func (oc *OC) EnableConsolePlugin() {
-	jsonOutput := oc.run("get", "consoles.operator.openshift.io", "cluster", "-o", "jsonpath={.spec.plugins}").Stdout()
-	log.Printf("Already enabled console plugins: %s", jsonOutput)
-	var plugins = make([]string, 0, 1)
-	if len(jsonOutput) > 0 {
-		err := json.Unmarshal([]byte(jsonOutput), &plugins)
-
-		if err != nil {
-			Fail(fmt.Sprintf("Could not parse consoles.operator.openshift.io CR: %v", err))
-		}
-
-		if slices.Contains(plugins, config.ConsolePluginDeployment) {
-			log.Printf("Pipelines console plugin is already enabled.")
-			return
-		}
+	type console struct {
+		Spec struct {
+			Plugins []string `json:"plugins"`
+		} `json:"spec"`
+	}
+	current, err := cmd.JSON[console](oc.getOcCommand([]string{"get", "consoles.operator.openshift.io", "cluster"})...)
+	if err != nil {
+		Fail(fmt.Sprintf("Could not read consoles.operator.openshift.io CR: %v", err))
+	}
+	plugins := current.Spec.Plugins
+	log.Printf("Already enabled console plugins: %v", plugins)
+	if slices.Contains(plugins, config.ConsolePluginDeployment) {
+		log.Printf("Pipelines console plugin is already enabled.")
+		return
 	}
 
 	plugins = append(plugins, config.ConsolePluginDeployment)
@@ -381,6 +402,7 @@ func (oc *OC) GetSecretsData(secretName, namespace string) string {
 
 // CreateChainsImageRegistrySecret creates the chains image registry credentials secret.
 func (oc *OC) CreateChainsImageRegistrySecret(dockerConfig string) {
+	redact.Register(dockerConfig)
 	ns := store.Namespace()
 	if ns == "" {
 		panic("CreateChainsImageRegistrySecret: store.Namespace() is empty - ensure hooks are configured")
@@ -403,6 +425,7 @@ func (oc *OC) ValidateAndCreateJibMavenSecret(namespace string) {
 	}
 
 	// Create secret with docker config
+	redact.Register(dockerConfig)
 	oc.run("create", "secret", "generic", "jib-maven-image-registry-credentials",
 		"--from-literal=.dockerconfigjson="+dockerConfig,
 		"--from-literal=config.json="+dockerConfig,
@@ -415,28 +438,22 @@ func (oc *OC) ValidateAndCreateJibMavenSecret(namespace string) {
 
 // CopySecret copies a secret from one namespace to another, transforming metadata and data keys.
 func (oc *OC) CopySecret(secretName, sourceNamespace, destNamespace string) {
-	secretJSON := oc.run("get", "secret", secretName, "-n", sourceNamespace, "-o", "json").Stdout()
-
 	// Process in Go instead of piping through shell to avoid injection
-	var secret map[string]any
-	Expect(json.Unmarshal([]byte(secretJSON), &secret)).To(Succeed(), "failed to parse secret JSON")
+	secret, err := cmd.Unstructured(oc.getOcCommand([]string{"get", "secret", secretName, "-n", sourceNamespace})...)
+	Expect(err).NotTo(HaveOccurred(), "failed to read secret %s/%s", sourceNamespace, secretName)
 
 	// Remove metadata fields
-	if meta, ok := secret["metadata"].(map[string]any); ok {
-		for _, key := range []string{"namespace", "creationTimestamp", "resourceVersion", "selfLink", "uid", "annotations"} {
-			delete(meta, key)
-		}
+	for _, key := range []string{"namespace", "creationTimestamp", "resourceVersion", "selfLink", "uid", "annotations"} {
+		unstructured.RemoveNestedField(secret.Object, "metadata", key)
 	}
 
 	// Rename "github-auth-key" to "token" in data
-	if data, ok := secret["data"].(map[string]any); ok {
-		if val, exists := data["github-auth-key"]; exists {
-			data["token"] = val
-			delete(data, "github-auth-key")
-		}
+	if val, exists, _ := unstructured.NestedString(secret.Object, "data", "github-auth-key"); exists {
+		Expect(unstructured.SetNestedField(secret.Object, val, "data", "token")).To(Succeed())
+		unstructured.RemoveNestedField(secret.Object, "data", "github-auth-key")
 	}
 
-	cleanedJSON, err := json.Marshal(secret)
+	cleanedJSON, err := secret.MarshalJSON()
 	Expect(err).NotTo(HaveOccurred(), "failed to marshal cleaned secret")
 
 	tmpFile, err := os.CreateTemp("", "secret-*.json")
@@ -491,21 +508,54 @@ func RemoveSCCFromUser(scc, userRef string) {
 
 // ── internal helpers ──────────────────────────────────────────────────────────
 
+// apiClients caches the clients OC sends API requests with, by kube context.
+var apiClients sync.Map
+
+// tektonConfigs returns the TektonConfig client of the cluster the oc commands
+// target, connecting with the flags cmd.Command passes to oc.
+func (oc *OC) tektonConfigs() operatorv1alpha1.TektonConfigInterface {
+	contextName := cmp.Or(oc.Context, config.Flags.Context)
+	cs, ok := apiClients.Load(contextName)
+	if !ok {
+		created, err := clients.NewClientsWithContext(config.Flags.Kubeconfig, config.Flags.Cluster, contextName, config.TargetNamespace)
+		Expect(err).NotTo(HaveOccurred(), "failed to create clients for kube context %q", contextName)
+		cs, _ = apiClients.LoadOrStore(contextName, created)
+	}
+	return cs.(*clients.Clients).Operator.TektonConfigs()
+}
+
+// requestContext bounds the API requests of oc like its commands.
+func (oc *OC) requestContext() context.Context {
+	if oc.ctx != nil {
+		return oc.ctx
+	}
+	return context.TODO()
+}
+
 func (oc *OC) runWithLog(args ...string) {
 	log.Printf("output: %s\n", oc.run(args...).Stdout())
 }
 
-func (oc *OC) run(args ...string) *icmd.Result {
+func (oc *OC) run(args ...string) *cmd.Result {
 	command := oc.getOcCommand(args)
+	if oc.ctx != nil {
+		return cmd.MustSucceedContext(oc.ctx, command...)
+	}
 	return cmd.MustSucceed(command...)
 }
 
-func (oc *OC) runIgnoreErrors(args ...string) *icmd.Result {
+func (oc *OC) runIgnoreErrors(args ...string) *cmd.Result {
 	command := oc.getOcCommand(args)
+	if oc.ctx != nil {
+		return cmd.RunContext(oc.ctx, command...)
+	}
 	return cmd.Run(command...)
 }
-func (oc *OC) runIncreasedTimeout(timeout time.Duration, args ...string) *icmd.Result {
+func (oc *OC) runIncreasedTimeout(timeout time.Duration, args ...string) *cmd.Result {
 	command := oc.getOcCommand(args)
+	if oc.ctx != nil {
+		return cmd.MustSucceedIncreasedTimeoutContext(oc.ctx, timeout, command...)
+	}
 	return cmd.MustSucceedIncreasedTimeout(timeout, command...)
 }
 
@@ -0,0 +1,56 @@
+package oc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd/cmdtest"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+func TestCreateReplay(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	path := config.Path("testdata/pvc/pvc.yaml")
+	cmdtest.Replay(t, cmd.Interaction{
+		Args:   []string{"oc", "create", "-f", path, "-n", "demo"},
+		Stdout: "persistentvolumeclaim/shared-pvc created\n",
+	}, cmd.Interaction{
+		Args:   []string{"oc", "--context", "admin", "create", "-f", path, "-n", "demo"},
+		Stdout: "persistentvolumeclaim/shared-pvc created\n",
+	}, cmd.Interaction{
+		Args:     []string{"oc", "create", "-f", path, "-n", "demo"},
+		Stderr:   `Error from server (AlreadyExists): persistentvolumeclaims "shared-pvc" already exists`,
+		ExitCode: 1,
+	})
+
+	(&OC{}).Create("testdata/pvc/pvc.yaml", "demo")
+	(&OC{Context: "admin"}).WithContext(context.Background()).Create("testdata/pvc/pvc.yaml", "demo")
+	failures := gomega.InterceptGomegaFailures(func() {
+		(&OC{}).Create("testdata/pvc/pvc.yaml", "demo")
+	})
+	if len(failures) != 1 || !strings.Contains(failures[0], "AlreadyExists") {
+		t.Fatalf("failures = %q, want the failed create reported with its stderr", failures)
+	}
+}
+
+func TestSecretExistsReplay(t *testing.T) {
+	cmdtest.Replay(t, cmd.Interaction{
+		Args:   []string{"oc", "get", "secret", "github-auth-secret", "-n", "demo"},
+		Stdout: "NAME                 TYPE     DATA   AGE\ngithub-auth-secret   Opaque   1      5s\n",
+	}, cmd.Interaction{
+		Args:     []string{"oc", "get", "secret", "github-auth-secret", "-n", "empty"},
+		Stderr:   `Error from server (NotFound): secrets "github-auth-secret" not found`,
+		ExitCode: 1,
+	})
+
+	if !(&OC{}).SecretExists("github-auth-secret", "demo") {
+		t.Fatal("SecretExists() = false for an existing secret")
+	}
+	if (&OC{}).SecretExists("github-auth-secret", "empty") {
+		t.Fatal("SecretExists() = true for a missing secret")
+	}
+}
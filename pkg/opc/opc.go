@@ -2,6 +2,7 @@
 package opc
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -18,6 +19,8 @@ import (
 type Cmd struct {
 	// path to opc binary
 	Path string
+	// ctx, when set, bounds every command run through this Cmd.
+	ctx context.Context
 }
 
 // PipelineRunList holds the name and status of a PipelineRun.
@@ -174,6 +177,13 @@ func ValidateQuickstarts() {
 	cmd.MustSucceed("oc", "get", "consolequickstart", "configure-pipeline-metrics").Stdout()
 }
 
+// WithContext returns a copy of opc whose commands are killed once ctx is done.
+// Pass a Ginkgo SpecContext so opc does not outlive an interrupted spec.
+func (opc Cmd) WithContext(ctx context.Context) Cmd {
+	opc.ctx = ctx
+	return opc
+}
+
 // MustSucceed runs opc with the given arguments and fails the test on non-zero exit.
 func (opc Cmd) MustSucceed(args ...string) string {
 	return opc.Assert(icmd.Success, args...)
@@ -182,6 +192,9 @@ func (opc Cmd) MustSucceed(args ...string) string {
 // Assert runs opc with the given arguments and asserts the expected result.
 func (opc Cmd) Assert(exp icmd.Expected, args ...string) string {
 	run := append([]string{opc.Path}, args...)
+	if opc.ctx != nil {
+		return cmd.AssertContext(opc.ctx, exp, run...).Stdout()
+	}
 	output := cmd.Assert(exp, run...)
 	return output.Stdout()
 }
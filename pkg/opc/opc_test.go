@@ -0,0 +1,30 @@
+package opc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd/cmdtest"
+)
+
+func TestVerifyResourceListMatchesNameReplay(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	cmdtest.Replay(t, cmd.Interaction{
+		Args:   []string{"opc", "pipelinerun", "list", "-n", "demo"},
+		Stdout: "NAME        STARTED         DURATION   STATUS\nbuild-run   1 minute ago    20s        Succeeded\n",
+	}, cmd.Interaction{
+		Args:   []string{"opc", "pipelinerun", "list", "-n", "empty"},
+		Stdout: "No PipelineRuns found\n",
+	})
+
+	if _, err := VerifyResourceListMatchesName("pipelinerun", "build-run", "demo"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := VerifyResourceListMatchesName("pipelinerun", "build-run", "empty")
+	if err == nil || !strings.Contains(err.Error(), `"empty"`) {
+		t.Fatalf("VerifyResourceListMatchesName() error = %v, want not found in namespace", err)
+	}
+}
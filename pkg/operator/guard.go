@@ -0,0 +1,257 @@
+package operator
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive,staticcheck // dot import is idiomatic for Gomega
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/test/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
+)
+
+// GuardFieldManager is the server-side apply field manager used when a
+// ClusterStateGuard restores TektonConfig.
+const GuardFieldManager = "release-tests-guard"
+
+// tektonConfigResource is read and written through the dynamic client so the
+// guard keeps spec fields the typed v1alpha1 spec does not know.
+var tektonConfigResource = v1alpha1.SchemeGroupVersion.WithResource("tektonconfigs")
+
+// ClusterStateGuard holds the TektonConfig spec and APIServer TLS profile taken
+// before a spec changes them, so they can be put back afterwards.
+type ClusterStateGuard struct {
+	cs   *clients.Clients
+	name string
+	spec map[string]any
+	// tlsCaptured is false on clusters without APIServer/cluster, e.g. plain
+	// Kubernetes, where the TLS profile is neither compared nor restored.
+	tlsCaptured bool
+	tlsProfile  *configv1.TLSSecurityProfile
+	// Timeout bounds the wait for TektonConfig to become ready with the restored
+	// spec. It defaults to config.APITimeout.
+	Timeout time.Duration
+}
+
+// GuardClusterState captures the TektonConfig spec and APIServer TLS profile and
+// registers a DeferCleanup that restores them, waits for TektonConfig to become
+// ready and fails the spec when the cluster does not converge back. Call it from
+// BeforeAll of Serial containers, or BeforeEach, before anything is patched:
+//
+//	BeforeAll(func() {
+//		operator.GuardClusterState(sharedClients, store.GetCRNames())
+//	})
+func GuardClusterState(cs *clients.Clients, names utils.ResourceNames) {
+	guard, err := CaptureClusterState(context.TODO(), cs, names)
+	Expect(err).NotTo(HaveOccurred(), "failed to capture cluster state before the spec")
+	DeferCleanup(func(ctx SpecContext) {
+		Expect(guard.Restore(ctx)).To(Succeed(), "cluster state leaked: restoring TektonConfig did not converge")
+	})
+}
+
+// CaptureClusterState records the spec of TektonConfig names.TektonConfig and
+// the TLS profile of APIServer/cluster.
+func CaptureClusterState(ctx context.Context, cs *clients.Clients, names utils.ResourceNames) (*ClusterStateGuard, error) {
+	g := &ClusterStateGuard{cs: cs, name: names.TektonConfig, Timeout: config.APITimeout}
+	spec, err := g.currentSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.spec = spec
+
+	if cs.ProxyConfig == nil {
+		return g, nil
+	}
+	apiServer, err := cs.ProxyConfig.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err) || meta.IsNoMatchError(err):
+		log.Printf("APIServer/cluster not available, not guarding the TLS profile: %v", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get APIServer/cluster: %w", err)
+	default:
+		g.tlsCaptured = true
+		g.tlsProfile = apiServer.Spec.TLSSecurityProfile.DeepCopy()
+	}
+	return g, nil
+}
+
+// Drift lists how the cluster differs from the captured state, in the format of
+// DiffHealth. It is empty when nothing changed.
+func (g *ClusterStateGuard) Drift(ctx context.Context) (HealthDiff, error) {
+	spec, err := g.currentSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before := &HealthSnapshot{Entries: map[string]string{}}
+	after := &HealthSnapshot{Entries: map[string]string{}}
+	flatten(before.Entries, "TektonConfig/"+g.name+" spec", g.spec)
+	flatten(after.Entries, "TektonConfig/"+g.name+" spec", spec)
+	diff := DiffHealth(before, after)
+
+	if g.tlsCaptured {
+		profile, err := g.currentTLSProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !equality.Semantic.DeepEqual(profile, g.tlsProfile) {
+			diff = append(diff, fmt.Sprintf("~ APIServer/cluster spec.tlsSecurityProfile: %s -> %s",
+				tlsProfileString(g.tlsProfile), tlsProfileString(profile)))
+		}
+	}
+	return diff, nil
+}
+
+// Restore puts the captured state back when it drifted: the TLS profile is
+// replaced as a whole, since it is a union, and the TektonConfig spec is
+// server-side applied with GuardFieldManager, after which fields that were
+// added since the capture are removed. It then waits until TektonConfig is
+// ready with the captured spec, and returns the remaining drift if it is not
+// within g.Timeout.
+func (g *ClusterStateGuard) Restore(ctx context.Context) error {
+	drift, err := g.Drift(ctx)
+	if err != nil {
+		return err
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	log.Printf("Restoring cluster state changed by the spec:\n%s", drift)
+
+	if g.tlsCaptured {
+		if err := g.restoreTLSProfile(ctx); err != nil {
+			return err
+		}
+	}
+	if err := g.restoreTektonConfig(ctx); err != nil {
+		return err
+	}
+
+	var lastDrift HealthDiff
+	var ready bool
+	err = wait.PollUntilContextTimeout(ctx, config.APIRetry, g.Timeout, true, func(ctx context.Context) (bool, error) {
+		tc, err := g.cs.Operator.TektonConfigs().Get(ctx, g.name, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("Waiting for TektonConfig %s: %v", g.name, err)
+			return false, nil
+		}
+		ready = tc.Status.IsReady() && tc.Status.ObservedGeneration >= tc.Generation
+		if lastDrift, err = g.Drift(ctx); err != nil {
+			log.Printf("Waiting for TektonConfig %s: %v", g.name, err)
+			return false, nil
+		}
+		return ready && len(lastDrift) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("TektonConfig %s not restored within %s (ready=%t), remaining drift:\n%s",
+			g.name, g.Timeout, ready, lastDrift)
+	}
+	log.Printf("Restored TektonConfig %s and it is ready", g.name)
+	return nil
+}
+
+// restoreTektonConfig applies the captured spec and then patches what server-side
+// apply left different, such as fields added since by another field manager.
+func (g *ClusterStateGuard) restoreTektonConfig(ctx context.Context) error {
+	apply, err := json.Marshal(map[string]any{
+		"apiVersion": "operator.tekton.dev/v1alpha1",
+		"kind":       "TektonConfig",
+		"metadata":   map[string]any{"name": g.name},
+		"spec":       g.spec,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode TektonConfig apply: %w", err)
+	}
+	force := true
+	_, err = g.cs.Dynamic.Resource(tektonConfigResource).Patch(ctx, g.name, types.ApplyPatchType, apply,
+		metav1.PatchOptions{FieldManager: GuardFieldManager, Force: &force})
+	if err != nil {
+		return fmt.Errorf("failed to apply TektonConfig %s: %w", g.name, err)
+	}
+
+	spec, err := g.currentSpec(ctx)
+	if err != nil {
+		return err
+	}
+	ops := tektonconfig.JSONPatchOps("/spec", spec, g.spec)
+	if len(ops) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("failed to encode TektonConfig patch: %w", err)
+	}
+	if _, err := g.cs.Dynamic.Resource(tektonConfigResource).Patch(ctx, g.name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch TektonConfig %s back to the captured spec: %w", g.name, err)
+	}
+	return nil
+}
+
+// restoreTLSProfile replaces the APIServer/cluster TLS profile with the captured one.
+func (g *ClusterStateGuard) restoreTLSProfile(ctx context.Context) error {
+	profile, err := g.currentTLSProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(profile, g.tlsProfile) {
+		return nil
+	}
+	op := map[string]any{"op": "add", "path": "/spec/tlsSecurityProfile", "value": g.tlsProfile}
+	if g.tlsProfile == nil {
+		op = map[string]any{"op": "remove", "path": "/spec/tlsSecurityProfile"}
+	}
+	patch, err := json.Marshal([]any{op})
+	if err != nil {
+		return fmt.Errorf("failed to encode TLS profile patch: %w", err)
+	}
+	if _, err := g.cs.ProxyConfig.APIServers().Patch(ctx, "cluster", types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to restore APIServer/cluster TLS profile: %w", err)
+	}
+	return nil
+}
+
+// currentSpec returns the TektonConfig spec as the server stores it, including
+// fields the typed v1alpha1 spec of the operator version in go.mod lacks.
+func (g *ClusterStateGuard) currentSpec(ctx context.Context) (map[string]any, error) {
+	tc, err := g.cs.Dynamic.Resource(tektonConfigResource).Get(ctx, g.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TektonConfig %s: %w", g.name, err)
+	}
+	spec, _, err := unstructured.NestedMap(tc.Object, "spec")
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec in TektonConfig %s: %w", g.name, err)
+	}
+	return spec, nil
+}
+
+func (g *ClusterStateGuard) currentTLSProfile(ctx context.Context) (*configv1.TLSSecurityProfile, error) {
+	apiServer, err := g.cs.ProxyConfig.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIServer/cluster: %w", err)
+	}
+	return apiServer.Spec.TLSSecurityProfile, nil
+}
+
+// tlsProfileString renders a TLS profile type, or "default" when none is set.
+func tlsProfileString(p *configv1.TLSSecurityProfile) string {
+	if p == nil {
+		return "default"
+	}
+	return cmp.Or(string(p.Type), "unset")
+}
@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+)
+
+func TestClusterStateGuardRestore(t *testing.T) {
+	ctx := context.Background()
+	fake := clients.NewFakeClients(readyTektonConfig())
+	fake.DynamicFake.PrependReactor("patch", "tektonconfigs", applySpecReactor(t, fake))
+	// A field the typed v1alpha1 spec does not know must survive the restore.
+	updateSpec(t, fake, func(spec map[string]any) {
+		spec["futureFeature"] = map[string]any{"enabled": true}
+	})
+
+	guard, err := CaptureClusterState(ctx, fake.Clients, utils.ResourceNames{TektonConfig: "config"})
+	if err != nil {
+		t.Fatalf("CaptureClusterState() error = %v", err)
+	}
+
+	updateSpec(t, fake, func(spec map[string]any) {
+		spec["pruner"] = map[string]any{"keep": int64(5), "schedule": "*/1 * * * *"}
+		spec["futureFeature"] = map[string]any{"enabled": false}
+		spec["params"] = []any{map[string]any{"name": "createRbacResource", "value": "false"}}
+	})
+
+	drift, err := guard.Drift(ctx)
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+	for _, want := range []string{
+		"~ TektonConfig/config spec.pruner.keep: 100 -> 5",
+		"+ TektonConfig/config spec.pruner.schedule = */1 * * * *",
+		"~ TektonConfig/config spec.futureFeature.enabled: true -> false",
+	} {
+		if !strings.Contains(drift.String(), want) {
+			t.Errorf("Drift() = %q, want it to contain %q", drift, want)
+		}
+	}
+
+	if err := guard.Restore(ctx); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if drift, err := guard.Drift(ctx); err != nil || len(drift) != 0 {
+		t.Errorf("Drift() after Restore() = %q, %v, want no changes", drift, err)
+	}
+	tc, err := fake.DynamicFake.Resource(tektonConfigResource).Get(ctx, "config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedSlice(tc.Object, "spec", "params"); found {
+		t.Errorf("Restore() kept spec.params added after the capture: %v", tc.Object["spec"])
+	}
+}
+
+func TestClusterStateGuardRestoreNotConverging(t *testing.T) {
+	ctx := context.Background()
+	fake := clients.NewFakeClients(readyTektonConfig())
+	// An operator that keeps reverting the spec: patches are accepted but ignored.
+	fake.DynamicFake.PrependReactor("patch", "tektonconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := fake.DynamicFake.Tracker().Get(action.GetResource(), "", action.(k8stesting.PatchAction).GetName())
+		return true, obj, err
+	})
+
+	guard, err := CaptureClusterState(ctx, fake.Clients, utils.ResourceNames{TektonConfig: "config"})
+	if err != nil {
+		t.Fatalf("CaptureClusterState() error = %v", err)
+	}
+	guard.Timeout = 10 * time.Millisecond
+
+	updateSpec(t, fake, func(spec map[string]any) {
+		spec["pruner"] = map[string]any{"keep": int64(5)}
+	})
+
+	err = guard.Restore(ctx)
+	if err == nil || !strings.Contains(err.Error(), "spec.pruner.keep: 100 -> 5") {
+		t.Errorf("Restore() error = %v, want the remaining drift", err)
+	}
+}
+
+func readyTektonConfig() *v1alpha1.TektonConfig {
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		Spec:       v1alpha1.TektonConfigSpec{Pruner: v1alpha1.Prune{Keep: ptr(uint(100))}},
+	}
+	tc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+	return tc
+}
+
+// updateSpec edits the spec of TektonConfig config as the dynamic client serves it.
+func updateSpec(t *testing.T, fake *clients.FakeClients, edit func(spec map[string]any)) {
+	t.Helper()
+	ctx := context.Background()
+	tc, err := fake.DynamicFake.Resource(tektonConfigResource).Get(ctx, "config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, _, _ := unstructured.NestedMap(tc.Object, "spec")
+	if spec == nil {
+		spec = map[string]any{}
+	}
+	edit(spec)
+	if err := unstructured.SetNestedMap(tc.Object, spec, "spec"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fake.DynamicFake.Resource(tektonConfigResource).Update(ctx, tc, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// applySpecReactor serves server-side apply patches, which the fake clientset
+// does not support, by replacing the spec with the applied one.
+func applySpecReactor(t *testing.T, fake *clients.FakeClients) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		var applied struct {
+			Spec map[string]any `json:"spec"`
+		}
+		if err := json.Unmarshal(patch.GetPatch(), &applied); err != nil {
+			t.Fatalf("invalid apply patch: %v", err)
+		}
+		obj, err := fake.DynamicFake.Tracker().Get(action.GetResource(), "", patch.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		tc := obj.(*unstructured.Unstructured)
+		tc.Object["spec"] = applied.Spec
+		return true, tc, fake.DynamicFake.Tracker().Update(action.GetResource(), tc, "")
+	}
+}
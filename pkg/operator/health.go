@@ -0,0 +1,269 @@
+package operator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// HealthSnapshot records the operator-managed state of a cluster at one point in
+// time, so two snapshots can be compared with DiffHealth. Every entry is a flat
+// "key = value" pair; the keys are grouped by prefix:
+//
+//	TektonConfig/config spec.pruner.keep = 100
+//	TektonConfig/config ready = True
+//	TektonInstallerSet/pipeline-main-deployment-x ready = True
+//	Deployment/openshift-pipelines/tekton-pipelines-controller generation = 3
+//	Pod/openshift-pipelines/tekton-pipelines-controller-abc/controller restarts = 0
+//	ValidatingWebhookConfiguration/validation.webhook.pipeline.tekton.dev webhooks = ...
+type HealthSnapshot struct {
+	TakenAt time.Time
+	Entries map[string]string
+	// Errors lists what could not be read, e.g. TektonChain on clusters without Chains.
+	Errors []string
+}
+
+// restartsSuffix marks container restart count entries, which DiffHealth only
+// reports when they grow.
+const restartsSuffix = " restarts"
+
+// TakeHealthSnapshot records the spec and readiness of TektonConfig, TektonPipeline,
+// TektonTrigger and TektonChain, the readiness of every TektonInstallerSet, the
+// generations of deployments and the container restart counts in the operator
+// and target namespaces, and the Tekton admission webhook configurations.
+// Resources that cannot be read are listed in Errors instead of failing.
+func TakeHealthSnapshot(ctx context.Context, cs *clients.Clients) *HealthSnapshot {
+	s := &HealthSnapshot{TakenAt: time.Now().UTC(), Entries: map[string]string{}}
+
+	components := []struct {
+		kind string
+		list func() (runtime.Object, error)
+	}{
+		{"TektonConfig", func() (runtime.Object, error) { return cs.Operator.TektonConfigs().List(ctx, metav1.ListOptions{}) }},
+		{"TektonPipeline", func() (runtime.Object, error) { return cs.Operator.TektonPipelines().List(ctx, metav1.ListOptions{}) }},
+		{"TektonTrigger", func() (runtime.Object, error) { return cs.Operator.TektonTriggers().List(ctx, metav1.ListOptions{}) }},
+		{"TektonChain", func() (runtime.Object, error) { return cs.Operator.TektonChains().List(ctx, metav1.ListOptions{}) }},
+	}
+	for _, component := range components {
+		list, err := component.list()
+		if err != nil {
+			s.addError(component.kind, err)
+			continue
+		}
+		s.addComponents(component.kind, list)
+	}
+
+	installerSets, err := cs.Operator.TektonInstallerSets().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.addError("TektonInstallerSet", err)
+	} else {
+		for _, is := range installerSets.Items {
+			s.set("TektonInstallerSet/"+is.Name, "ready", readiness(is.Status.GetCondition(apis.ConditionReady)))
+		}
+	}
+
+	for _, ns := range slices.Compact([]string{config.Flags.PipelinesOperatorNamespace, config.TargetNamespace}) {
+		deployments, err := cs.KubeClient.Kube.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			s.addError("Deployment/"+ns, err)
+		} else {
+			for _, d := range deployments.Items {
+				key := "Deployment/" + ns + "/" + d.Name
+				s.set(key, "generation", strconv.FormatInt(d.Generation, 10))
+				s.set(key, "ready replicas", fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Status.Replicas))
+			}
+		}
+		pods, err := cs.KubeClient.Kube.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			s.addError("Pod/"+ns, err)
+			continue
+		}
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				s.Entries["Pod/"+ns+"/"+pod.Name+"/"+status.Name+restartsSuffix] = strconv.Itoa(int(status.RestartCount))
+			}
+		}
+	}
+
+	validating, err := cs.KubeClient.Kube.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.addError("ValidatingWebhookConfiguration", err)
+	} else {
+		for _, wc := range validating.Items {
+			if isTektonWebhook(wc.Name) {
+				s.set("ValidatingWebhookConfiguration/"+wc.Name, "webhooks", validatingWebhooks(wc.Webhooks))
+			}
+		}
+	}
+	mutating, err := cs.KubeClient.Kube.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.addError("MutatingWebhookConfiguration", err)
+	} else {
+		for _, wc := range mutating.Items {
+			if isTektonWebhook(wc.Name) {
+				s.set("MutatingWebhookConfiguration/"+wc.Name, "webhooks", mutatingWebhooks(wc.Webhooks))
+			}
+		}
+	}
+	return s
+}
+
+// addComponents records the flattened spec, version and readiness of every
+// object in a component list.
+func (s *HealthSnapshot) addComponents(kind string, list runtime.Object) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(list)
+	if err != nil {
+		s.addError(kind, err)
+		return
+	}
+	items, _ := content["items"].([]any)
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		metadata, _ := obj["metadata"].(map[string]any)
+		key := fmt.Sprintf("%s/%v", kind, metadata["name"])
+		flatten(s.Entries, key+" spec", obj["spec"])
+		status, _ := obj["status"].(map[string]any)
+		if version, ok := status["version"]; ok {
+			s.set(key, "version", fmt.Sprint(version))
+		}
+		ready := "Unknown"
+		conditions, _ := status["conditions"].([]any)
+		for _, c := range conditions {
+			if condition, ok := c.(map[string]any); ok && condition["type"] == string(apis.ConditionReady) {
+				ready = fmt.Sprint(condition["status"])
+			}
+		}
+		s.set(key, "ready", ready)
+	}
+}
+
+func (s *HealthSnapshot) set(key, field, value string) {
+	s.Entries[key+" "+field] = value
+}
+
+func (s *HealthSnapshot) addError(what string, err error) {
+	if apierrs.IsNotFound(err) {
+		err = fmt.Errorf("not installed")
+	}
+	s.Errors = append(s.Errors, fmt.Sprintf("%s: %v", what, err))
+}
+
+// flatten stores every leaf of v under its dotted path below prefix. Lists are
+// stored whole, so reordering shows up as one change.
+func flatten(entries map[string]string, prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			flatten(entries, prefix+"."+k, child)
+		}
+	case nil:
+	default:
+		entries[prefix] = fmt.Sprint(v)
+	}
+}
+
+// readiness renders a Ready condition, or "Unknown" when it is missing.
+func readiness(c *apis.Condition) string {
+	if c == nil {
+		return "Unknown"
+	}
+	return string(c.Status)
+}
+
+// isTektonWebhook reports whether a webhook configuration belongs to Tekton
+// components or Pipelines as Code.
+func isTektonWebhook(name string) bool {
+	return strings.Contains(name, "tekton.dev") || strings.Contains(name, "pipelines")
+}
+
+// validatingWebhooks renders the webhooks of a configuration without their CA
+// bundles, which rotate on their own.
+func validatingWebhooks(webhooks []admissionregistrationv1.ValidatingWebhook) string {
+	parts := make([]string, 0, len(webhooks))
+	for _, w := range webhooks {
+		parts = append(parts, webhookSummary(w.Name, w.FailurePolicy, w.ClientConfig, len(w.Rules)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// mutatingWebhooks renders the webhooks of a configuration without their CA bundles.
+func mutatingWebhooks(webhooks []admissionregistrationv1.MutatingWebhook) string {
+	parts := make([]string, 0, len(webhooks))
+	for _, w := range webhooks {
+		parts = append(parts, webhookSummary(w.Name, w.FailurePolicy, w.ClientConfig, len(w.Rules)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func webhookSummary(name string, policy *admissionregistrationv1.FailurePolicyType, client admissionregistrationv1.WebhookClientConfig, rules int) string {
+	failurePolicy := "default"
+	if policy != nil {
+		failurePolicy = string(*policy)
+	}
+	target := "url"
+	if client.Service != nil {
+		target = client.Service.Namespace + "/" + client.Service.Name
+	}
+	return fmt.Sprintf("%s (failurePolicy=%s, service=%s, rules=%d)", name, failurePolicy, target, rules)
+}
+
+// HealthDiff lists the changes between two snapshots, one line per entry.
+type HealthDiff []string
+
+// DiffHealth compares two snapshots. Added, removed and changed entries are
+// reported, except that restart counts are only reported when they grew and
+// pods that went away are ignored, since rollouts replace pods.
+func DiffHealth(before, after *HealthSnapshot) HealthDiff {
+	var diff HealthDiff
+	union := maps.Clone(before.Entries)
+	maps.Copy(union, after.Entries)
+	for _, key := range slices.Sorted(maps.Keys(union)) {
+		old, hadOld := before.Entries[key]
+		value, hasNew := after.Entries[key]
+		if strings.HasSuffix(key, restartsSuffix) {
+			if hasNew && atoi(value) > atoi(old) {
+				diff = append(diff, fmt.Sprintf("~ %s: %s -> %s", key, cmp.Or(old, "0"), value))
+			}
+			continue
+		}
+		switch {
+		case !hadOld:
+			diff = append(diff, fmt.Sprintf("+ %s = %s", key, value))
+		case !hasNew:
+			diff = append(diff, fmt.Sprintf("- %s = %s", key, old))
+		case old != value:
+			diff = append(diff, fmt.Sprintf("~ %s: %s -> %s", key, old, value))
+		}
+	}
+	return diff
+}
+
+// String renders the diff, or "no changes".
+func (d HealthDiff) String() string {
+	if len(d) == 0 {
+		return "no changes"
+	}
+	return strings.Join(d, "\n")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
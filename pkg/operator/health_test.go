@@ -0,0 +1,84 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+func TestHealthSnapshotDiff(t *testing.T) {
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		Spec:       v1alpha1.TektonConfigSpec{Pruner: v1alpha1.Prune{Keep: ptr(uint(100))}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "tekton-pipelines-controller-abc", Namespace: config.TargetNamespace},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "controller"}}},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "tekton-pipelines-controller", Namespace: config.TargetNamespace, Generation: 1,
+	}}
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validation.webhook.pipeline.tekton.dev"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "validation.webhook.pipeline.tekton.dev"}},
+	}
+	kube := kubefake.NewClientset(pod, deployment, webhook)
+	ops := operatorfake.NewSimpleClientset(tc)
+	cs := &clients.Clients{KubeClient: &clients.KubeClient{Kube: kube}, Operator: ops.OperatorV1alpha1()}
+	ctx := context.Background()
+
+	before := TakeHealthSnapshot(ctx, cs)
+	if got := before.Entries["TektonConfig/config spec.pruner.keep"]; got != "100" {
+		t.Fatalf("pruner keep = %q, want 100; entries: %v", got, before.Entries)
+	}
+	if diff := DiffHealth(before, TakeHealthSnapshot(ctx, cs)); len(diff) != 0 {
+		t.Fatalf("unchanged cluster reported changes:\n%s", diff)
+	}
+
+	// A spec changes the pruner, triggers a rollout and crashes the controller.
+	tc.Spec.Pruner.Keep = ptr(uint(5))
+	if _, err := ops.OperatorV1alpha1().TektonConfigs().Update(ctx, tc, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deployment.Generation = 2
+	if _, err := kube.AppsV1().Deployments(config.TargetNamespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	pod.Status.ContainerStatuses[0].RestartCount = 3
+	if _, err := kube.CoreV1().Pods(config.TargetNamespace).Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := DiffHealth(before, TakeHealthSnapshot(ctx, cs)).String()
+	want := strings.Join([]string{
+		"~ Deployment/openshift-pipelines/tekton-pipelines-controller generation: 1 -> 2",
+		"~ Pod/openshift-pipelines/tekton-pipelines-controller-abc/controller restarts: 0 -> 3",
+		"~ TektonConfig/config spec.pruner.keep: 100 -> 5",
+	}, "\n")
+	if got != want {
+		t.Fatalf("diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffHealthIgnoresReplacedPods(t *testing.T) {
+	before := &HealthSnapshot{Entries: map[string]string{"Pod/ns/old/c restarts": "4"}}
+	after := &HealthSnapshot{Entries: map[string]string{"Pod/ns/new/c restarts": "0"}}
+	if diff := DiffHealth(before, after); len(diff) != 0 {
+		t.Fatalf("rollout reported as changes:\n%s", diff)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
 	. "github.com/onsi/gomega"    //nolint:revive,staticcheck // dot import is idiomatic for Gomega
 
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	"github.com/tektoncd/operator/test/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -23,6 +25,7 @@ import (
 	occmd "github.com/openshift-pipelines/release-tests-ginkgo/pkg/oc"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/openshift"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/statefulset"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 )
 
 var oc = occmd.OC{}
@@ -30,8 +33,9 @@ var oc = occmd.OC{}
 // DefineArtifactHubAPIVariable patches TektonConfig to set the artifact-hub-api
 // URL for the hub resolver, pointing to https://artifacthub.io/.
 func DefineArtifactHubAPIVariable() {
-	patchData := `{"spec":{"pipeline":{"hub-resolver-config":{"artifact-hub-api":"https://artifacthub.io/"}}}}`
-	oc.UpdateTektonConfig(patchData)
+	oc.PatchTektonConfig(tektonconfig.NewPatch().Pipeline(func(p *v1alpha1.Pipeline) {
+		p.HubResolverConfig = setKeys(p.HubResolverConfig, map[string]string{"artifact-hub-api": "https://artifacthub.io/"})
+	}))
 }
 
 // VerifyNamespaceExists waits for a namespace to exist, polling until it
@@ -53,7 +57,7 @@ func VerifyNamespaceExists(namespace string) {
 // ConfigureGitResolverToken configures the GitHub token for git resolver in TektonConfig.
 // If GITHUB_TOKEN is set and the secret does not already exist, it creates the secret
 // and patches TektonConfig to reference it.
-func ConfigureGitResolverToken(_ *clients.Clients) {
+func ConfigureGitResolverToken(cs *clients.Clients) {
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
 		log.Printf("Token for authorization to the GitHub repository was not exported as a system variable")
@@ -64,15 +68,27 @@ func ConfigureGitResolverToken(_ *clients.Clients) {
 	} else {
 		log.Printf("Secret \"github-auth-secret\" already exists")
 	}
-	patchData := `{"spec":{"pipeline":{"git-resolver-config":{"api-token-secret-key":"github-auth-key","api-token-secret-name":"github-auth-secret","api-token-secret-namespace":"openshift-pipelines","default-revision":"main","fetch-timeout":"1m","scm-type":"github"}}}}`
-	oc.UpdateTektonConfig(patchData)
+	PatchTektonConfig(cs, tektonconfig.NewPatch().Pipeline(func(p *v1alpha1.Pipeline) {
+		p.GitResolverConfig = setKeys(p.GitResolverConfig, map[string]string{
+			"api-token-secret-key":       "github-auth-key",
+			"api-token-secret-name":      "github-auth-secret",
+			"api-token-secret-namespace": "openshift-pipelines",
+			"default-revision":           "main",
+			"fetch-timeout":              "1m",
+			"scm-type":                   "github",
+		})
+	}))
 }
 
 // ConfigureBundlesResolver patches TektonConfig to configure the bundles resolver
 // with default-kind and default-service-account.
-func ConfigureBundlesResolver(_ *clients.Clients) {
-	patchData := `{"spec":{"pipeline":{"bundles-resolver-config":{"default-kind":"task","defaut-service-account":"pipelines"}}}}`
-	oc.UpdateTektonConfig(patchData)
+func ConfigureBundlesResolver(cs *clients.Clients) {
+	PatchTektonConfig(cs, tektonconfig.NewPatch().Pipeline(func(p *v1alpha1.Pipeline) {
+		p.BundlesResolverConfig = setKeys(p.BundlesResolverConfig, map[string]string{
+			"default-kind":           "task",
+			"defaut-service-account": "pipelines",
+		})
+	}))
 }
 
 // EnableConsolePluginOperator enables the pipelines console plugin.
@@ -100,24 +116,43 @@ func EnableConsolePluginOperator(cs *clients.Clients) {
 
 // EnableStatefulSet patches TektonConfig to enable StatefulSet mode for pipelines
 // with HA, statefulset ordinals, 2 replicas and 2 buckets.
-func EnableStatefulSet(_ *clients.Clients) {
-	patchData := `{"spec":{"pipeline":{"performance":{"disable-ha":false,"statefulset-ordinals":true,"replicas":2,"buckets":2}}}}`
-	oc.UpdateTektonConfig(patchData)
+func EnableStatefulSet(cs *clients.Clients) {
+	PatchTektonConfig(cs, tektonconfig.NewPatch().Pipeline(func(p *v1alpha1.Pipeline) {
+		enableStatefulSetOrdinals(&p.Performance)
+	}))
 }
 
 // EnableStatefulSetForComponent enables statefulset for a specific component
 // (chains or results) in TektonConfig.
-func EnableStatefulSetForComponent(_ *clients.Clients, component string) {
-	var patchData string
+func EnableStatefulSetForComponent(cs *clients.Clients, component string) {
+	patch := tektonconfig.NewPatch()
 	switch component {
 	case "chains":
-		patchData = `{"spec":{"chain":{"performance":{"disable-ha":false,"statefulset-ordinals":true,"replicas":2,"buckets":2}}}}`
+		patch.Chain(func(c *v1alpha1.Chain) { enableStatefulSetOrdinals(&c.Performance) })
 	case "results":
-		patchData = `{"spec":{"result":{"performance":{"disable-ha":false,"statefulset-ordinals":true,"replicas":2,"buckets":2}}}}`
+		patch.Result(func(r *v1alpha1.Result) { enableStatefulSetOrdinals(&r.Performance) })
 	default:
 		Fail(fmt.Sprintf("unsupported component: %s. Cannot generate patch data for statefulset", component))
 	}
-	oc.UpdateTektonConfig(patchData)
+	PatchTektonConfig(cs, patch)
+}
+
+// enableStatefulSetOrdinals runs a component as an HA StatefulSet with 2
+// replicas and 2 buckets.
+func enableStatefulSetOrdinals(p *v1alpha1.PerformanceProperties) {
+	p.DisableHA = false
+	p.StatefulsetOrdinals = new(true)
+	p.Replicas = new(int32(2))
+	p.Buckets = new(uint(2))
+}
+
+// setKeys returns m with the given keys set, allocating it when nil.
+func setKeys(m, keys map[string]string) map[string]string {
+	if m == nil {
+		m = map[string]string{}
+	}
+	maps.Copy(m, keys)
+	return m
 }
 
 // ValidateTriggersDeployment validates the triggers deployment.
@@ -135,17 +170,17 @@ func ValidatePACDeployment(cs *clients.Clients) {
 // EnableChainsSigningSecret enables generateSigningSecret for Tekton Chains
 // in TektonConfig. If the signing-secrets secret does not exist or is empty,
 // it creates/patches as needed.
-func EnableChainsSigningSecret(_ *clients.Clients) {
-	patchData := `{"spec":{"chain":{"generateSigningSecret":true}}}`
+func EnableChainsSigningSecret(cs *clients.Clients) {
+	patch := tektonconfig.NewPatch().Chain(func(c *v1alpha1.Chain) { c.GenerateSigningSecret = true })
 	if oc.SecretExists("signing-secrets", "openshift-pipelines") {
 		log.Printf("Secrets \"signing-secrets\" already exists")
 		if oc.GetSecretsData("signing-secrets", "openshift-pipelines") == "\"\"" {
 			log.Printf("The \"signing-secrets\" does not contain any data")
-			oc.UpdateTektonConfig(patchData)
+			PatchTektonConfig(cs, patch)
 		}
 	} else {
 		cmd.MustSucceed("oc", "create", "secret", "generic", "signing-secrets", "--namespace", "openshift-pipelines")
-		oc.UpdateTektonConfig(patchData)
+		PatchTektonConfig(cs, patch)
 	}
 }
 
@@ -199,9 +234,14 @@ func ValidateConsolePluginDeployment(cs *clients.Clients) {
 
 // ConfigureResultsWithLoki patches TektonConfig to configure Results with Loki
 // integration for log storage.
-func ConfigureResultsWithLoki(_ *clients.Clients) {
-	patchData := `{"spec":{"result":{"auth_disable":true,"disabled":false,"log_level":"debug","loki_stack_name":"logging-loki","loki_stack_namespace":"openshift-logging"}}}`
-	oc.UpdateTektonConfig(patchData)
+func ConfigureResultsWithLoki(cs *clients.Clients) {
+	PatchTektonConfig(cs, tektonconfig.NewPatch().Result(func(r *v1alpha1.Result) {
+		r.AuthDisable = new(true)
+		r.Disabled = false
+		r.LogLevel = "debug"
+		r.LokiStackName = "logging-loki"
+		r.LokiStackNamespace = "openshift-logging"
+	}))
 }
 
 // VerifyTektonAddonsStatus verifies that the TektonAddon CR is ready
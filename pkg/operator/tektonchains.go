@@ -30,6 +30,7 @@ import (
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	chainv1alpha "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	"github.com/tektoncd/operator/test/utils"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,6 +39,7 @@ import (
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 )
 
 // "quay.io/openshift-pipeline/chainstest"
@@ -64,19 +66,27 @@ func EnsureTektonChainsExists(clients chainv1alpha.TektonChainInterface, names u
 // UpdateTektonConfigForChains patches the TektonConfig CR to configure Tekton Chains
 // with the given format, taskrun storage, OCI storage, and transparency settings.
 func UpdateTektonConfigForChains(format, taskrunStorage, ociStorage, transparency string) {
-	patchData := fmt.Sprintf(`{"spec":{"chain":{"options":{"disabled":false},"artifacts.taskrun.format":"%s","artifacts.taskrun.storage":"%s","artifacts.oci.storage":"%s","transparency.enabled":"%s"}}}`,
-		format, taskrunStorage, ociStorage, transparency)
-	cmd.MustSucceed("oc", "patch", "tektonconfig", "config", "-p", patchData, "--type=merge")
+	oc.PatchTektonConfig(chainsPatch(format, taskrunStorage, ociStorage, transparency))
 	log.Printf("Updated TektonConfig for chains: format=%s, taskrunStorage=%s, ociStorage=%s, transparency=%s", format, taskrunStorage, ociStorage, transparency)
 }
 
 // RestoreTektonConfigChains restores the TektonConfig chains settings to defaults.
 func RestoreTektonConfigChains() {
-	patchData := `{"spec":{"chain":{"options":{"disabled":false},"artifacts.taskrun.format":"in-toto","artifacts.taskrun.storage":"tekton","artifacts.oci.storage":"","transparency.enabled":"false"}}}`
-	cmd.MustSucceed("oc", "patch", "tektonconfig", "config", "-p", patchData, "--type=merge")
+	oc.PatchTektonConfig(chainsPatch("in-toto", "tekton", "", "false"))
 	log.Println("Restored TektonConfig chains settings to defaults")
 }
 
+// chainsPatch enables Chains with the given artifact and transparency settings.
+func chainsPatch(format, taskrunStorage, ociStorage, transparency string) *tektonconfig.Patch {
+	return tektonconfig.NewPatch().Chain(func(c *v1alpha1.Chain) {
+		c.Options.Disabled = new(false)
+		c.ArtifactsTaskRunFormat = format
+		c.ArtifactsTaskRunStorage = &taskrunStorage
+		c.ArtifactsOCIStorage = &ociStorage
+		c.TransparencyConfigEnabled = v1alpha1.BoolValue(transparency)
+	})
+}
+
 const (
 	chainsSignatureInterval = 10 * time.Second
 	chainsSignatureTimeout  = 3 * time.Minute
@@ -159,23 +169,15 @@ func GetImageURLAndDigest() (string, string, error) {
 	}
 	// Get Image digest
 	var imageDigest string
-	jsonOutput := cmd.MustSucceed("opc", "tr", "describe", "--last", "-o", "jsonpath={.status.results}", "-n", ns).Stdout()
-	// Parse Json Output
-	type Result struct {
-		Name  string `json:"name"`
-		Value string `json:"value"`
-	}
-
-	var results []Result
-	err := json.Unmarshal([]byte(jsonOutput), &results)
+	taskRun, err := cmd.JSON[pipelinev1.TaskRun]("opc", "tr", "describe", "--last", "-n", ns)
 	if err != nil {
-		return "", "", fmt.Errorf("error parsing JSON output: %w", err)
+		return "", "", fmt.Errorf("failed to describe the last TaskRun: %w", err)
 	}
 
 	// Get IMAGE_DIGEST value
-	for _, result := range results {
+	for _, result := range taskRun.Status.Results {
 		if strings.Contains(result.Name, "IMAGE_DIGEST") {
-			imageDigest = strings.Split(result.Value, ":")[1]
+			imageDigest = strings.Split(result.Value.StringVal, ":")[1]
 		}
 	}
 
@@ -279,8 +281,7 @@ func CreateFileWithCosignPubKey() error {
 		}
 		if attempt >= signingSecretRetryTrigger && !triggered {
 			log.Printf("  cosign.pub still empty after %d attempts — patching TektonConfig to trigger key generation", attempt)
-			patchData := `{"spec":{"chain":{"generateSigningSecret":true}}}`
-			cmd.MustSucceed("oc", "patch", "tektonconfig", "config", "-p", patchData, "--type=merge")
+			oc.PatchTektonConfig(tektonconfig.NewPatch().Chain(func(c *v1alpha1.Chain) { c.GenerateSigningSecret = true }))
 			triggered = true
 		}
 		log.Printf("  signing-secrets/cosign.pub not populated yet (attempt %d) — retrying in %s", attempt, signingSecretInterval)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	configv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
@@ -42,6 +43,14 @@ func EnsureTektonConfigExists(clients configv1alpha1.TektonConfigInterface, name
 	return tcCR, err
 }
 
+// PatchTektonConfig applies patch to the TektonConfig through the Operator
+// client, failing the spec when the patch does not match the CRD schema or the
+// cluster rejects it.
+func PatchTektonConfig(cs *clients.Clients, patch *tektonconfig.Patch) {
+	_, err := patch.Apply(context.TODO(), cs.Operator.TektonConfigs(), tektonconfig.DefaultName)
+	Expect(err).NotTo(HaveOccurred(), "failed to patch TektonConfig")
+}
+
 // EnsureTektonConfigStatusInstalled waits until TektonConfig CR reports InstallSucceeded.
 func EnsureTektonConfigStatusInstalled(clients configv1alpha1.TektonConfigInterface, names utils.ResourceNames) {
 	err := wait.PollUntilContextTimeout(context.TODO(), config.APIRetry, config.APITimeout, true, func(context.Context) (bool, error) {
@@ -127,18 +127,17 @@ func VerifyResultsLogs(resourceType string) error {
 	log.Printf("Waiting 10 seconds for Results API to index data\n")
 	time.Sleep(10 * time.Second)
 
-	var resultsJSONData = cmd.MustSucceed("opc", "results", "logs", "get", "--insecure", "--addr", resultsAPI, recordUUID).Stdout()
-	if strings.Contains(resultsJSONData, "record not found") {
-		return fmt.Errorf("results log not found")
-	}
-
 	type ResultLogs struct {
 		Name string `json:"name"`
 		Data string `json:"data"`
 	}
-	var resultLogs ResultLogs
-	err := json.Unmarshal([]byte(resultsJSONData), &resultLogs)
+	// "logs get" prints JSON without an output flag.
+	out, err := cmd.Output("opc", "results", "logs", "get", "--insecure", "--addr", resultsAPI, recordUUID)
 	if err != nil {
+		return fmt.Errorf("failed to get results logs of record %s: %w", recordUUID, err)
+	}
+	var resultLogs ResultLogs
+	if err := json.Unmarshal([]byte(out), &resultLogs); err != nil {
 		return fmt.Errorf("error parsing JSON: %w", err)
 	}
 	decodedResultsLogs, err := base64.StdEncoding.Strict().DecodeString(resultLogs.Data)
@@ -163,22 +162,15 @@ func VerifyResultsRecords(resourceType string) error {
 	log.Printf("Waiting 10 seconds for Results API to index data\n")
 	time.Sleep(10 * time.Second)
 
-	var resultsRecord = cmd.MustSucceed("opc", "results", "records", "get", "--insecure", "--addr", resultsAPI, recordUUID).Stdout()
-	if strings.Contains(resultsRecord, "record not found") {
-		return fmt.Errorf("results record not found")
-	}
-
 	type ResultRecords struct {
 		Data struct {
 			Type  string `json:"type"`
 			Value string `json:"value"`
 		} `json:"data"`
 	}
-	resultsJSONData := cmd.MustSucceed("opc", "results", "records", "get", "--insecure", "--addr", resultsAPI, recordUUID, "-o", "json").Stdout()
-	var resultRecords ResultRecords
-	err := json.Unmarshal([]byte(resultsJSONData), &resultRecords)
+	resultRecords, err := cmd.JSON[ResultRecords]("opc", "results", "records", "get", "--insecure", "--addr", resultsAPI, recordUUID)
 	if err != nil {
-		return fmt.Errorf("error parsing JSON: %w", err)
+		return fmt.Errorf("failed to get results record %s: %w", recordUUID, err)
 	}
 	decodedResultsLogs, err := base64.StdEncoding.Strict().DecodeString(resultRecords.Data.Value)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/k8s"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/pipelines"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
 )
 
 const (
@@ -325,6 +326,7 @@ func SetupGitHubProject(c *clients.Clients, namespace, smeeURL string) (owner, r
 		}
 		webhookSecret = sec
 	}
+	redact.Register(webhookSecret)
 
 	repoName = fmt.Sprintf("release-tests-pac-%08d", time.Now().UnixNano()%1e8)
 	createReq := &github.Repository{
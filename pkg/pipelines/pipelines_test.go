@@ -6,6 +6,11 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd/cmdtest"
 )
 
 func TestRunOCRejectsCommandFailure(t *testing.T) {
@@ -28,3 +33,24 @@ func TestRunOCRejectsCommandFailure(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestAssertNumberOfPipelinerunsReplay(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	list := []string{"oc", "get", "pipelinerun", "-n", "demo", "-o", "name"}
+	cmdtest.Replay(t, cmd.Interaction{
+		Args:   list,
+		Stdout: "pipelinerun.tekton.dev/build-1\npipelinerun.tekton.dev/build-2\n",
+	}, cmd.Interaction{
+		Args:     list,
+		Stderr:   "error: You must be logged in to the server (Unauthorized)",
+		ExitCode: 1,
+	})
+
+	AssertNumberOfPipelineruns("demo", 2, 5)
+	failures := gomega.InterceptGomegaFailures(func() {
+		AssertNumberOfPipelineruns("demo", 2, 0)
+	})
+	if len(failures) != 1 || !strings.Contains(failures[0], "exit code 1: error: You must be logged in") {
+		t.Fatalf("failures = %q, want the oc failure reported", failures)
+	}
+}
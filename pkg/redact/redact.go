@@ -0,0 +1,198 @@
+// Package redact scrubs secret material from command lines, logs, diagnostics and
+// Ginkgo report entries before they reach CI artifacts.
+//
+// Secrets are known either by value (Register, e.g. a generated webhook secret)
+// or by the environment variable holding them (RegisterEnv). Environment
+// variables are read at redaction time, so values loaded from properties files
+// after start-up are still covered.
+package redact
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces every redacted value.
+const Placeholder = "[REDACTED]"
+
+// minSecretLength keeps short, common strings from turning unrelated output
+// into noise.
+const minSecretLength = 4
+
+// DefaultEnv lists the environment variables that carry secrets in this repo.
+// Any variable ending in one of secretEnvSuffixes is also redacted.
+var DefaultEnv = []string{
+	"GITHUB_TOKEN",
+	"GITLAB_TOKEN",
+	"GITLAB_WEBHOOK_TOKEN",
+	"PAC_GITHUB_TOKEN",
+	"PAC_GITHUB_WEBHOOK_TOKEN",
+	"CHAINS_DOCKER_CONFIG_JSON",
+	"JIB_MAVEN_DOCKER_CONFIG_JSON",
+}
+
+// secretEnvSuffixes marks environment variables as secret by name, such as the
+// <USER>_PASS variables holding MAG user passwords.
+var secretEnvSuffixes = []string{"_TOKEN", "_PASS", "_PASSWORD", "_SECRET", "_DOCKER_CONFIG_JSON"}
+
+// sensitiveFlags are flag names whose values are always hidden in command lines.
+var sensitiveFlags = []string{"token", "password", "passwd", "secret", "from-literal"}
+
+var registry = struct {
+	mu       sync.RWMutex
+	values   map[string]bool
+	envNames map[string]bool
+	// replacer is rebuilt when the set of secret values changes.
+	replacer *strings.Replacer
+	snapshot string
+}{
+	values:   map[string]bool{},
+	envNames: map[string]bool{},
+}
+
+func init() {
+	RegisterEnv(DefaultEnv...)
+}
+
+// Register marks values as secret. Their standard base64 encodings are redacted
+// too, so the values stay hidden in "oc get secret -o yaml" output.
+func Register(values ...string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLength {
+			continue
+		}
+		registry.values[v] = true
+		registry.values[base64.StdEncoding.EncodeToString([]byte(v))] = true
+	}
+}
+
+// RegisterEnv marks the values of the named environment variables as secret.
+func RegisterEnv(names ...string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, name := range names {
+		registry.envNames[name] = true
+	}
+}
+
+// String replaces every registered secret in s with Placeholder.
+func String(s string) string {
+	if s == "" {
+		return s
+	}
+	return currentReplacer().Replace(s)
+}
+
+// Sprintf formats according to a format specifier and redacts the result.
+func Sprintf(format string, a ...any) string {
+	return String(fmt.Sprintf(format, a...))
+}
+
+// Args returns a copy of a command line with registered secrets and the values of
+// sensitive flags (--token, --password, --from-literal, "oc login -p", ...) redacted.
+func Args(args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = String(arg)
+	}
+	login := slices.Contains(args, "login")
+	for i := 0; i < len(out); i++ {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(out[i], "-"), "=")
+		if !strings.HasPrefix(out[i], "-") || !(isSensitiveFlag(name) || login && name == "p") {
+			continue
+		}
+		switch {
+		case hasValue:
+			keep := strings.Index(out[i], "=") + 1
+			if name == "from-literal" {
+				// --from-literal=key=value: the key is useful, the value is not.
+				keep += strings.Index(out[i][keep:], "=") + 1
+			}
+			out[i] = out[i][:keep] + Placeholder
+		case i+1 < len(out):
+			i++
+			key := ""
+			if name == "from-literal" {
+				key = fromLiteralKey(args[i])
+			}
+			out[i] = key + Placeholder
+		}
+	}
+	return out
+}
+
+// fromLiteralKey keeps the "key=" prefix of a separate --from-literal value.
+func fromLiteralKey(value string) string {
+	if key, _, ok := strings.Cut(value, "="); ok && !strings.Contains(key, " ") {
+		return key + "="
+	}
+	return ""
+}
+
+// isSensitiveFlag reports whether a flag name (without dashes) carries a secret.
+func isSensitiveFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveFlags {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentReplacer returns a replacer for the registered values plus the current
+// values of secret environment variables.
+func currentReplacer() *strings.Replacer {
+	registry.mu.RLock()
+	secrets := make([]string, 0, len(registry.values))
+	for v := range registry.values {
+		secrets = append(secrets, v)
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if (registry.envNames[name] || hasSecretSuffix(name)) && len(strings.TrimSpace(value)) >= minSecretLength {
+			value = strings.TrimSpace(value)
+			secrets = append(secrets, value, base64.StdEncoding.EncodeToString([]byte(value)))
+		}
+	}
+	registry.mu.RUnlock()
+
+	// Longest first, so a secret containing another secret is replaced whole.
+	slices.SortFunc(secrets, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+	secrets = slices.Compact(secrets)
+	snapshot := strings.Join(secrets, "\x00")
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.replacer == nil || registry.snapshot != snapshot {
+		pairs := make([]string, 0, 2*len(secrets))
+		for _, s := range secrets {
+			pairs = append(pairs, s, Placeholder)
+		}
+		registry.replacer = strings.NewReplacer(pairs...)
+		registry.snapshot = snapshot
+	}
+	return registry.replacer
+}
+
+// hasSecretSuffix reports whether an environment variable name marks a secret.
+func hasSecretSuffix(name string) bool {
+	for _, suffix := range secretEnvSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
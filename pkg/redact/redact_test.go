@@ -0,0 +1,59 @@
+package redact
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringRedactsRegisteredValuesAndEnv(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "glpat-from-env")
+	t.Setenv("USER1_PASS", "s3cret-pass")
+	t.Setenv("UNRELATED", "glpat")
+	Register("generated-webhook-secret", "abc")
+
+	input := strings.Join([]string{
+		"token=glpat-from-env",
+		"password: s3cret-pass",
+		"webhook.secret: " + base64.StdEncoding.EncodeToString([]byte("generated-webhook-secret")),
+		"name: abc",
+	}, "\n")
+	want := strings.Join([]string{
+		"token=" + Placeholder,
+		"password: " + Placeholder,
+		"webhook.secret: " + Placeholder,
+		"name: abc",
+	}, "\n")
+	if got := String(input); got != want {
+		t.Fatalf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Values loaded into the environment after the first call are picked up.
+	t.Setenv("CHAINS_DOCKER_CONFIG_JSON", `{"auths":{}}`)
+	if got := Sprintf("config %s", `{"auths":{}}`); got != "config "+Placeholder {
+		t.Fatalf("Sprintf() = %q", got)
+	}
+}
+
+func TestArgsRedactsSensitiveFlags(t *testing.T) {
+	Register("registered-value")
+	got := Args([]string{
+		"oc", "create", "secret", "generic", "gitlab-webhook-config",
+		"--from-literal", "provider.token=plain", "--from-literal=webhook.secret=plain",
+		"--token=plain", "--password", "plain", "-p", "kept", "-n", "registered-value",
+	})
+	want := []string{
+		"oc", "create", "secret", "generic", "gitlab-webhook-config",
+		"--from-literal", "provider.token=" + Placeholder, "--from-literal=webhook.secret=" + Placeholder,
+		"--token=" + Placeholder, "--password", Placeholder, "-p", "kept", "-n", Placeholder,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Args() =\n%q\nwant\n%q", got, want)
+	}
+
+	login := Args([]string{"oc", "login", "https://api:6443", "-u", "user1", "-p", "plain"})
+	if login[len(login)-1] != Placeholder || login[4] != "user1" {
+		t.Fatalf("oc login password was not redacted: %q", login)
+	}
+}
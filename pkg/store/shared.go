@@ -0,0 +1,117 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// sharedKeys maps the name of every shared key to the function that decodes and
+// stores its broadcast value on the receiving node.
+var sharedKeys = struct {
+	mu     sync.RWMutex
+	decode map[string]func(json.RawMessage) error
+}{decode: map[string]func(json.RawMessage) error{}}
+
+// suiteData is the envelope passed from node 1 to every node through the
+// SynchronizedBeforeSuite byte payload.
+type suiteData struct {
+	Payload json.RawMessage            `json:"payload,omitempty"`
+	Shared  map[string]json.RawMessage `json:"shared,omitempty"`
+}
+
+// NewSharedKey declares a suite-scoped key whose value, when set on node 1 during
+// SynchronizedBeforeSuite, is broadcast to every parallel node. T must round-trip
+// through encoding/json.
+//
+// Usage in suite_test.go:
+//
+//	var smeeURL = store.NewSharedKey[string]("smee-url")
+//
+//	var _ = SynchronizedBeforeSuite(func() []byte {
+//		smeeURL.Set(setupSmee())
+//		data, err := store.EncodeSuiteData(cfg)
+//		Expect(err).NotTo(HaveOccurred())
+//		return data
+//	}, func(data []byte) {
+//		Expect(store.DecodeSuiteData(data, &cfg)).To(Succeed())
+//		url := smeeURL.Get() // set on every node
+//	})
+func NewSharedKey[T any](name string) Key[T] {
+	key := NewKey[T](name, ScopeSuite)
+	sharedKeys.mu.Lock()
+	defer sharedKeys.mu.Unlock()
+	sharedKeys.decode[name] = func(raw json.RawMessage) error {
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		key.Set(v)
+		return nil
+	}
+	return key
+}
+
+// EncodeSuiteData serializes payload (typically the suite's client config) along
+// with every shared key set on this node. Pass nil when there is no payload.
+func EncodeSuiteData(payload any) ([]byte, error) {
+	var data suiteData
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("store: failed to encode suite payload: %w", err)
+		}
+		data.Payload = raw
+	}
+
+	sharedKeys.mu.RLock()
+	names := make([]string, 0, len(sharedKeys.decode))
+	for name := range sharedKeys.decode {
+		names = append(names, name)
+	}
+	sharedKeys.mu.RUnlock()
+
+	typed.mu.RLock()
+	defer typed.mu.RUnlock()
+	for _, name := range names {
+		v, ok := typed.suite[name]
+		if !ok {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("store: failed to encode shared key %q: %w", name, err)
+		}
+		if data.Shared == nil {
+			data.Shared = map[string]json.RawMessage{}
+		}
+		data.Shared[name] = raw
+	}
+	return json.Marshal(data)
+}
+
+// DecodeSuiteData restores the shared keys broadcast by node 1 and decodes the
+// payload into payload, which must be a pointer (or nil to ignore it).
+func DecodeSuiteData(data []byte, payload any) error {
+	var envelope suiteData
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("store: failed to decode suite data: %w", err)
+	}
+	if payload != nil && len(envelope.Payload) > 0 {
+		if err := json.Unmarshal(envelope.Payload, payload); err != nil {
+			return fmt.Errorf("store: failed to decode suite payload: %w", err)
+		}
+	}
+	for name, raw := range envelope.Shared {
+		sharedKeys.mu.RLock()
+		decode, ok := sharedKeys.decode[name]
+		sharedKeys.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("store: suite data for undeclared shared key %q", name)
+		}
+		if err := decode(raw); err != nil {
+			return fmt.Errorf("store: failed to decode shared key %q: %w", name, err)
+		}
+	}
+	return nil
+}
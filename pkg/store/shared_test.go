@@ -0,0 +1,55 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/test/utils"
+)
+
+func TestSuiteDataRoundTrip(t *testing.T) {
+	type clientConfig struct {
+		Kubeconfig string `json:"kubeconfig"`
+	}
+	smeeURL := NewSharedKey[string]("smee-url")
+	cliPaths := NewSharedKey[map[string]string]("cli-paths")
+	unset := NewSharedKey[int]("unset")
+
+	// Node 1.
+	smeeURL.Set("https://smee.io/abc")
+	cliPaths.Set(map[string]string{"opc": "/tmp/opc"})
+	SetCRNames(utils.ResourceNames{TektonConfig: "config"})
+	data, err := EncodeSuiteData(clientConfig{Kubeconfig: "/kube"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another node starts with an empty suite scope.
+	clear(typed.suite)
+	var cfg clientConfig
+	if err := DecodeSuiteData(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Kubeconfig != "/kube" {
+		t.Fatalf("payload = %+v", cfg)
+	}
+	if smeeURL.Get() != "https://smee.io/abc" || cliPaths.Get()["opc"] != "/tmp/opc" {
+		t.Fatalf("shared keys were not restored: %q %v", smeeURL.Get(), cliPaths.Get())
+	}
+	if GetCRNames().TektonConfig != "config" {
+		t.Fatalf("CR names were not broadcast: %+v", GetCRNames())
+	}
+	if _, ok := unset.Lookup(); ok {
+		t.Fatal("a key never set on node 1 was set by decoding")
+	}
+}
+
+func TestDecodeSuiteDataRejectsMismatches(t *testing.T) {
+	NewSharedKey[int]("count")
+	if err := DecodeSuiteData([]byte(`{"shared":{"count":"three"}}`), nil); err == nil || !strings.Contains(err.Error(), `"count"`) {
+		t.Fatalf("DecodeSuiteData() error = %v, want a decode error naming the key", err)
+	}
+	if err := DecodeSuiteData([]byte(`{"shared":{"typo":1}}`), nil); err == nil || !strings.Contains(err.Error(), "undeclared") {
+		t.Fatalf("DecodeSuiteData() error = %v, want an undeclared key error", err)
+	}
+}
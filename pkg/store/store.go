@@ -36,23 +36,23 @@ func Clients() *clients.Clients {
 	return nil
 }
 
+// Clusters holds the hub and spoke clients of multi-cluster suites.
+var Clusters = NewKey[*clients.ClusterSet]("clusters", ScopeSuite)
+
+// CRNames holds the operator CR names; set on node 1 it reaches every parallel node.
+var CRNames = NewSharedKey[utils.ResourceNames]("crnames")
+
 // GetCRNames returns the stored ResourceNames for the scenario.
 func GetCRNames() utils.ResourceNames {
-	mu.RLock()
-	defer mu.RUnlock()
-	if names, ok := scenarioStore["crnames"].(utils.ResourceNames); ok {
-		return names
-	}
-	return utils.ResourceNames{}
+	return CRNames.GetOr(utils.ResourceNames{})
 }
 
-// SetCRNames stores the ResourceNames for the current scenario so that
-// GetCRNames returns a non-empty value for test suites that do not use the
-// Gherkin step framework to seed the store.
+// SetCRNames stores the ResourceNames so that GetCRNames returns a non-empty
+// value for test suites that do not use the Gherkin step framework to seed the
+// store. Called on node 1 during SynchronizedBeforeSuite, the names are
+// broadcast to all nodes by EncodeSuiteData.
 func SetCRNames(names utils.ResourceNames) {
-	mu.Lock()
-	defer mu.Unlock()
-	scenarioStore["crnames"] = names
+	CRNames.Set(names)
 }
 
 // HTTPResponse returns the stored HTTP response for the scenario.
@@ -65,16 +65,6 @@ func HTTPResponse() *http.Response {
 	return nil
 }
 
-// GetPayload returns the stored payload bytes for the scenario.
-func GetPayload() []byte {
-	mu.RLock()
-	defer mu.RUnlock()
-	if p, ok := scenarioStore["payload"].([]byte); ok {
-		return p
-	}
-	return nil
-}
-
 // Opc returns the stored opc.Cmd for the suite, or panics if missing/wrong type.
 func Opc() opc.Cmd {
 	mu.RLock()
@@ -86,6 +76,8 @@ func Opc() opc.Cmd {
 }
 
 // PutScenarioData stores a string value under the given key for the scenario.
+//
+// Deprecated: use a Key, which is typed and scoped to the spec, container or suite.
 func PutScenarioData(key, value string) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -100,6 +92,8 @@ func SetNamespace(ns string) {
 }
 
 // PutScenarioDataSlice stores a string slice under the given key for the scenario.
+//
+// Deprecated: use a Key, which is typed and scoped to the spec, container or suite.
 func PutScenarioDataSlice(key string, value []string) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -107,6 +101,8 @@ func PutScenarioDataSlice(key string, value []string) {
 }
 
 // GetScenarioDataSlice retrieves a string slice stored under the given key.
+//
+// Deprecated: use a Key, which is typed and scoped to the spec, container or suite.
 func GetScenarioDataSlice(key string) []string {
 	mu.RLock()
 	defer mu.RUnlock()
@@ -117,6 +113,8 @@ func GetScenarioDataSlice(key string) []string {
 }
 
 // GetScenarioData retrieves a string stored under the given key.
+//
+// Deprecated: use a Key, which is typed and scoped to the spec, container or suite.
 func GetScenarioData(key string) string {
 	mu.RLock()
 	defer mu.RUnlock()
@@ -137,6 +135,8 @@ func TargetNamespace() string {
 }
 
 // PutSuiteData stores a value under the given key for the entire test suite.
+//
+// Deprecated: use a Key with ScopeSuite.
 func PutSuiteData(key string, value any) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -144,6 +144,8 @@ func PutSuiteData(key string, value any) {
 }
 
 // GetSuiteData retrieves a value stored under the given key for the suite.
+//
+// Deprecated: use a Key with ScopeSuite.
 func GetSuiteData(key string) any {
 	mu.RLock()
 	defer mu.RUnlock()
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/redact"
+)
+
+// maxDumpValueLength caps how much of each value Dump prints.
+const maxDumpValueLength = 200
+
+// Scope decides how long a value stored under a Key lives.
+type Scope int
+
+const (
+	// ScopeSpec values are cleared before every spec.
+	ScopeSpec Scope = iota
+	// ScopeContainer values are shared by the specs of one container, typically an
+	// Ordered Describe including its nested Contexts, and cleared when specs of
+	// the next container start.
+	ScopeContainer
+	// ScopeSuite values live for the whole suite process.
+	ScopeSuite
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeSpec:
+		return "spec"
+	case ScopeContainer:
+		return "container"
+	case ScopeSuite:
+		return "suite"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// Key is a typed handle to a value in the scoped store. Declare keys once as
+// package-level variables so a misspelt key is a compile error:
+//
+//	var prName = store.NewKey[string]("pipelinerun-name", store.ScopeContainer)
+//
+//	prName.Set(pr.Name)         // in one It of an Ordered Describe
+//	name := prName.Get()        // in a later It; panics if never set
+//
+// The lifecycle is driven by hooks.ScopedStore; without it every scope behaves
+// like ScopeSuite.
+type Key[T any] struct {
+	name  string
+	scope Scope
+}
+
+// scopedName identifies a key within its scope.
+type scopedName struct {
+	name  string
+	scope Scope
+}
+
+var typed = struct {
+	mu         sync.RWMutex
+	keyTypes   map[scopedName]reflect.Type
+	spec       map[string]any
+	containers map[string]map[string]any
+	suite      map[string]any
+	// container identifies the container scope of the running spec.
+	container string
+}{
+	keyTypes:   map[scopedName]reflect.Type{},
+	spec:       map[string]any{},
+	containers: map[string]map[string]any{},
+	suite:      map[string]any{},
+}
+
+// NewKey declares a key of type T. Declaring the same name and scope with a
+// different type panics, since both keys would address the same value.
+func NewKey[T any](name string, scope Scope) Key[T] {
+	t := reflect.TypeFor[T]()
+	id := scopedName{name: name, scope: scope}
+	typed.mu.Lock()
+	defer typed.mu.Unlock()
+	if existing, ok := typed.keyTypes[id]; ok && existing != t {
+		panic(fmt.Sprintf("store: %s key %q declared as both %s and %s", scope, name, existing, t))
+	}
+	typed.keyTypes[id] = t
+	return Key[T]{name: name, scope: scope}
+}
+
+// String returns the scope and name of the key.
+func (k Key[T]) String() string {
+	return k.scope.String() + "/" + k.name
+}
+
+// Set stores v under k in k's scope.
+func (k Key[T]) Set(v T) {
+	typed.mu.Lock()
+	defer typed.mu.Unlock()
+	k.values(true)[k.name] = v
+}
+
+// Lookup returns the value stored under k and whether it was set.
+func (k Key[T]) Lookup() (T, bool) {
+	typed.mu.RLock()
+	defer typed.mu.RUnlock()
+	v, ok := k.values(false)[k.name].(T)
+	return v, ok
+}
+
+// Get returns the value stored under k, or panics if it was never set in the
+// current scope, so a missing value fails the spec instead of reading as zero.
+func (k Key[T]) Get() T {
+	v, ok := k.Lookup()
+	if !ok {
+		panic(fmt.Sprintf("store: %s not set", k))
+	}
+	return v
+}
+
+// GetOr returns the value stored under k, or def if it was never set.
+func (k Key[T]) GetOr(def T) T {
+	if v, ok := k.Lookup(); ok {
+		return v
+	}
+	return def
+}
+
+// Delete removes the value stored under k.
+func (k Key[T]) Delete() {
+	typed.mu.Lock()
+	defer typed.mu.Unlock()
+	delete(k.values(false), k.name)
+}
+
+// values returns the map backing k's scope; callers hold typed.mu. A missing
+// container map is created only when create is set.
+func (k Key[T]) values(create bool) map[string]any {
+	switch k.scope {
+	case ScopeSpec:
+		return typed.spec
+	case ScopeContainer:
+		m, ok := typed.containers[typed.container]
+		if !ok && create {
+			m = map[string]any{}
+			typed.containers[typed.container] = m
+		}
+		return m
+	default:
+		return typed.suite
+	}
+}
+
+// EnterSpec starts a new spec in the given container scope: spec values are
+// cleared, and container values are dropped once a different container starts.
+// hooks.ScopedStore calls it before each spec with the code location of the
+// spec's outermost Ordered container.
+func EnterSpec(container string) {
+	typed.mu.Lock()
+	defer typed.mu.Unlock()
+	clear(typed.spec)
+	if container != typed.container {
+		delete(typed.containers, typed.container)
+		typed.container = container
+	}
+}
+
+// Dump lists every value visible to the running spec, one "scope/key = value"
+// per line with secrets redacted, for failure reports. It returns "" when the
+// store is empty.
+func Dump() string {
+	typed.mu.RLock()
+	defer typed.mu.RUnlock()
+	var lines []string
+	for _, scope := range []struct {
+		scope  Scope
+		values map[string]any
+	}{
+		{ScopeSpec, typed.spec},
+		{ScopeContainer, typed.containers[typed.container]},
+		{ScopeSuite, typed.suite},
+	} {
+		start := len(lines)
+		for name, v := range scope.values {
+			value := fmt.Sprintf("%v", v)
+			if len(value) > maxDumpValueLength {
+				value = value[:maxDumpValueLength] + "..."
+			}
+			lines = append(lines, fmt.Sprintf("%s/%s = %s", scope.scope, name, value))
+		}
+		slices.Sort(lines[start:])
+	}
+	return redact.String(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyScopes(t *testing.T) {
+	specKey := NewKey[int]("attempts", ScopeSpec)
+	containerKey := NewKey[string]("pipelinerun", ScopeContainer)
+	suiteKey := NewKey[[]string]("images", ScopeSuite)
+
+	EnterSpec("pre_upgrade_test.go:14")
+	specKey.Set(1)
+	containerKey.Set("pr-1")
+	suiteKey.Set([]string{"golang"})
+
+	EnterSpec("pre_upgrade_test.go:14")
+	if _, ok := specKey.Lookup(); ok {
+		t.Fatal("spec value survived into the next spec")
+	}
+	if got := containerKey.Get(); got != "pr-1" {
+		t.Fatalf("container value = %q, want it shared within the container", got)
+	}
+
+	EnterSpec("post_upgrade_test.go:18")
+	if got := containerKey.GetOr("none"); got != "none" {
+		t.Fatalf("container value leaked into another container: %q", got)
+	}
+	EnterSpec("pre_upgrade_test.go:14")
+	if _, ok := containerKey.Lookup(); ok {
+		t.Fatal("container value was not dropped when its container ended")
+	}
+	if got := suiteKey.Get(); len(got) != 1 || got[0] != "golang" {
+		t.Fatalf("suite value = %v", got)
+	}
+	suiteKey.Delete()
+}
+
+func TestGetPanicsWhenUnset(t *testing.T) {
+	key := NewKey[string]("never-set", ScopeSpec)
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), `spec/never-set not set`) {
+			t.Fatalf("Get() recovered %v, want a not-set panic", r)
+		}
+	}()
+	EnterSpec("")
+	key.Get()
+}
+
+func TestNewKeyRejectsConflictingTypes(t *testing.T) {
+	NewKey[string]("conflict", ScopeSuite)
+	NewKey[string]("conflict", ScopeSpec)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("redeclaring a key with another type did not panic")
+		}
+	}()
+	NewKey[int]("conflict", ScopeSuite)
+}
+
+func TestDumpListsVisibleValues(t *testing.T) {
+	EnterSpec("dump")
+	NewKey[string]("b", ScopeSpec).Set("second")
+	NewKey[string]("a", ScopeSpec).Set("first")
+	NewKey[string]("long", ScopeContainer).Set(strings.Repeat("x", 2*maxDumpValueLength))
+
+	dump := Dump()
+	lines := strings.Split(dump, "\n")
+	if len(lines) < 3 || lines[0] != "spec/a = first" || lines[1] != "spec/b = second" {
+		t.Fatalf("unexpected dump:\n%s", dump)
+	}
+	if !strings.HasPrefix(lines[2], "container/long = xxx") || !strings.HasSuffix(lines[2], "...") {
+		t.Fatalf("long value was not truncated: %q", lines[2])
+	}
+}
@@ -0,0 +1,87 @@
+package tektonconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"knative.dev/pkg/apis"
+	webhookjson "knative.dev/pkg/webhook/json"
+)
+
+// Messages the operator webhook rejects invalid TektonConfig changes with. The
+// e2e specs look for them in oc patch output, the offline tests in Admit errors.
+const (
+	KeepAndKeepSinceMessage                 = "expected exactly one, got both: spec.pruner.keep, spec.pruner.keep-since"
+	PipelineTemplatesWithoutResolverMessage = "pipelineTemplates cannot be true if resolverTask is false"
+	NegativeTTLMessage                      = "ttlSecondsAfterFinished cannot be negative"
+	WrongTypeMessage                        = "cannot unmarshal string"
+)
+
+// InvalidValueMessage is the message the operator webhook rejects an unknown
+// enum value such as a pruner resource with.
+func InvalidValueMessage(value string) string {
+	return "invalid value: " + value
+}
+
+// Resource is an operator CR with webhook defaulting and validation, such as
+// TektonConfig, TektonPipeline or TektonChain.
+type Resource interface {
+	apis.Defaultable
+	apis.Validatable
+}
+
+// Admit runs the operator webhook's defaulting and validation on obj
+// in-process. Use apis.WithinUpdate to validate obj as an update of an
+// existing resource; otherwise it is validated as a create.
+//
+// The operator reads the PLATFORM environment variable to choose between its
+// OpenShift and Kubernetes checks; set PLATFORM=openshift to get what the
+// OpenShift webhook does. Checks that need a cluster, such as whether a
+// non-default SCC exists, are not supported.
+func Admit(ctx context.Context, obj Resource) error {
+	if !apis.IsInUpdate(ctx) {
+		ctx = apis.WithinCreate(ctx)
+	}
+	obj.SetDefaults(ctx)
+	if errs := obj.Validate(ctx); errs != nil {
+		return fmt.Errorf("validation failed: %w", errs)
+	}
+	return nil
+}
+
+// AdmitJSON decodes data into obj like the webhook decodes the admission
+// request, so values of the wrong type fail as they do on the cluster, and
+// then calls Admit.
+func AdmitJSON(ctx context.Context, data []byte, obj Resource) error {
+	if err := webhookjson.Decode(data, obj, false); err != nil {
+		return fmt.Errorf("cannot decode incoming new object: %w", err)
+	}
+	return Admit(ctx, obj)
+}
+
+// Admit runs the operator webhook on the TektonConfig that results from
+// applying the patch to base, as an update of base. A nil base stands for a
+// new, empty TektonConfig named DefaultName.
+func (p *Patch) Admit(ctx context.Context, base *v1alpha1.TektonConfig) error {
+	_, after, err := p.specs(base)
+	if err != nil {
+		return err
+	}
+	name := DefaultName
+	if base != nil {
+		name = base.Name
+		ctx = apis.WithinUpdate(ctx, base)
+	}
+	data, err := json.Marshal(map[string]any{
+		"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+		"kind":       v1alpha1.KindTektonConfig,
+		"metadata":   map[string]any{"name": name},
+		"spec":       after,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode patched TektonConfig: %w", err)
+	}
+	return AdmitJSON(ctx, data, &v1alpha1.TektonConfig{})
+}
@@ -0,0 +1,110 @@
+package tektonconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// prune sets spec.pruner like oc.UpdatePrunerConfig does.
+func prune(keep, keepSince *uint, resources ...string) *Patch {
+	return NewPatch().Pruner(func(p *v1alpha1.Prune) {
+		p.Schedule = "*/8 * * * *"
+		p.Resources = resources
+		p.Keep = keep
+		p.KeepSince = keepSince
+	})
+}
+
+// globalConfig sets a tektonpruner global-config field like
+// oc.SetTektonPrunerGlobalConfig does.
+func globalConfig(value any, field string) *Patch {
+	return NewPatch().
+		TektonPruner(func(p *v1alpha1.Pruner) { p.Disabled = new(false) }).
+		Set(value, "tektonpruner", "global-config", field)
+}
+
+// TestPatchAdmit replays the negative cases of tests/operator offline.
+func TestPatchAdmit(t *testing.T) {
+	t.Setenv("PLATFORM", "openshift")
+	two := new(uint(2))
+
+	tests := []struct {
+		name    string
+		patch   *Patch
+		wantErr string
+	}{{
+		name:  "valid pruner",
+		patch: prune(two, nil, "pipelinerun", "taskrun"),
+	}, {
+		name:    "keep and keep-since",
+		patch:   prune(two, two, "pipelinerun", "taskrun"),
+		wantErr: KeepAndKeepSinceMessage,
+	}, {
+		name:    "invalid pruner resource taskrunas",
+		patch:   prune(two, nil, "pipelinerun", "taskrunas"),
+		wantErr: InvalidValueMessage("taskrunas"),
+	}, {
+		name:    "invalid pruner resource pipelinerunas",
+		patch:   prune(two, nil, "pipelinerunas", "taskrun"),
+		wantErr: InvalidValueMessage("pipelinerunas"),
+	}, {
+		name:    "pipeline templates without resolver tasks",
+		patch:   NewPatch().AddonParam("resolverTasks", "false").AddonParam("pipelineTemplates", "true"),
+		wantErr: PipelineTemplatesWithoutResolverMessage,
+	}, {
+		name:  "valid ttl",
+		patch: globalConfig(60, "ttlSecondsAfterFinished"),
+	}, {
+		name:    "negative ttl",
+		patch:   globalConfig(-1, "ttlSecondsAfterFinished"),
+		wantErr: NegativeTTLMessage,
+	}, {
+		name:    "ttl with unit",
+		patch:   globalConfig("60s", "ttlSecondsAfterFinished"),
+		wantErr: WrongTypeMessage,
+	}, {
+		name:    "history limit not a number",
+		patch:   globalConfig("not-a-number", "successfulHistoryLimit"),
+		wantErr: WrongTypeMessage,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patch.Admit(context.Background(), baseTektonConfig())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Admit() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Admit() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdmitComponents(t *testing.T) {
+	t.Setenv("PLATFORM", "openshift")
+	ctx := context.Background()
+
+	pipeline := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.PipelineResourceName}}
+	pipeline.Spec.TargetNamespace = "openshift-pipelines"
+	if err := Admit(ctx, pipeline); err != nil {
+		t.Errorf("Admit(TektonPipeline) error = %v", err)
+	}
+	pipeline.Spec.EnableApiFields = "experimental"
+	if err := Admit(ctx, pipeline); err == nil || !strings.Contains(err.Error(), "spec.enable-api-fields") {
+		t.Errorf("Admit(TektonPipeline) error = %v, want an enable-api-fields error", err)
+	}
+
+	chain := `{"apiVersion":"operator.tekton.dev/v1alpha1","kind":"TektonChain","metadata":{"name":"chain"},
+		"spec":{"targetNamespace":"openshift-pipelines","artifacts.taskrun.format":"slsa/v3"}}`
+	err := AdmitJSON(ctx, []byte(chain), &v1alpha1.TektonChain{})
+	if err == nil || !strings.Contains(err.Error(), InvalidValueMessage("slsa/v3")) {
+		t.Errorf("AdmitJSON(TektonChain) error = %v, want an invalid format error", err)
+	}
+}
@@ -0,0 +1,370 @@
+// Package tektonconfig builds TektonConfig patches from the operator's typed
+// v1alpha1.TektonConfigSpec and checks them against the TektonConfig CRD schema
+// before they are sent to the cluster.
+//
+// A Patch is a list of edits to the spec. The merge or JSON patch it produces
+// is the difference those edits make to a base TektonConfig, normally the live
+// one, so list fields such as spec.params are updated entry by entry instead of
+// replaced wholesale and fields the edits do not touch are left alone:
+//
+//	patch := tektonconfig.NewPatch().
+//		Param("createRbacResource", "false").
+//		Pruner(func(p *v1alpha1.Prune) { p.Schedule = "*/1 * * * *" })
+//	_, err := patch.Apply(ctx, cs.Operator.TektonConfigs(), tektonconfig.DefaultName)
+package tektonconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultName is the name of the TektonConfig the operator creates.
+const DefaultName = "config"
+
+// Patch is a declarative change to a TektonConfig spec. Build it with NewPatch
+// and the setters below; the zero value is an empty patch.
+type Patch struct {
+	edits []func(*v1alpha1.TektonConfigSpec)
+	// raw holds edits of fields the typed spec cannot express, applied after
+	// the typed edits.
+	raw []rawEdit
+	// unchecked makes Apply skip Validate.
+	unchecked bool
+}
+
+// rawEdit sets value at fields below spec, or removes the field when remove
+// is set.
+type rawEdit struct {
+	fields []string
+	value  any
+	remove bool
+}
+
+// NewPatch returns an empty patch.
+func NewPatch() *Patch {
+	return &Patch{}
+}
+
+// Spec edits the whole spec, for fields without a dedicated setter.
+func (p *Patch) Spec(edit func(*v1alpha1.TektonConfigSpec)) *Patch {
+	p.edits = append(p.edits, edit)
+	return p
+}
+
+// Pruner edits spec.pruner, the legacy cron job based pruner.
+func (p *Patch) Pruner(edit func(*v1alpha1.Prune)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.Pruner) })
+}
+
+// TektonPruner edits spec.tektonpruner, the event based pruner.
+func (p *Patch) TektonPruner(edit func(*v1alpha1.Pruner)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.TektonPruner) })
+}
+
+// Chain edits spec.chain.
+func (p *Patch) Chain(edit func(*v1alpha1.Chain)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.Chain) })
+}
+
+// Pipeline edits spec.pipeline.
+func (p *Patch) Pipeline(edit func(*v1alpha1.Pipeline)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.Pipeline) })
+}
+
+// Result edits spec.result.
+func (p *Patch) Result(edit func(*v1alpha1.Result)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.Result) })
+}
+
+// Platforms edits spec.platforms.
+func (p *Patch) Platforms(edit func(*v1alpha1.Platforms)) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { edit(&s.Platforms) })
+}
+
+// Param sets the spec.params entry name to value, adding it when missing.
+func (p *Patch) Param(name, value string) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { s.Params = setParam(s.Params, name, value) })
+}
+
+// AddonParam sets the spec.addon.params entry name to value, adding it when
+// missing.
+func (p *Patch) AddonParam(name, value string) *Patch {
+	return p.Spec(func(s *v1alpha1.TektonConfigSpec) { s.Addon.Params = setParam(s.Addon.Params, name, value) })
+}
+
+// Set sets the field below spec at the given path to value, which must encode
+// to JSON. It covers fields the typed spec does not model or cannot hold, such
+// as schemaless pruner settings or values of the wrong type for negative tests:
+//
+//	NewPatch().Set(int64(60), "tektonpruner", "global-config", "ttlSecondsAfterFinished")
+func (p *Patch) Set(value any, fields ...string) *Patch {
+	p.raw = append(p.raw, rawEdit{fields: fields, value: value})
+	return p
+}
+
+// Remove removes the field below spec at the given path.
+func (p *Patch) Remove(fields ...string) *Patch {
+	p.raw = append(p.raw, rawEdit{fields: fields, remove: true})
+	return p
+}
+
+// Unchecked makes Apply send the patch without validating it first, for specs
+// asserting that the cluster rejects it.
+func (p *Patch) Unchecked() *Patch {
+	p.unchecked = true
+	return p
+}
+
+// Build returns base with the patch applied. base is not modified; a nil base
+// stands for an empty TektonConfig.
+func (p *Patch) Build(base *v1alpha1.TektonConfig) (*v1alpha1.TektonConfig, error) {
+	_, after, err := p.specs(base)
+	if err != nil {
+		return nil, err
+	}
+	tc := &v1alpha1.TektonConfig{}
+	if base != nil {
+		tc = base.DeepCopy()
+	}
+	tc.Spec = v1alpha1.TektonConfigSpec{}
+	if err := convert(after, &tc.Spec); err != nil {
+		return nil, fmt.Errorf("patched TektonConfig spec does not fit the typed spec: %w", err)
+	}
+	return tc, nil
+}
+
+// MergePatch returns the JSON merge patch (RFC 7386) that turns base into the
+// patched TektonConfig. Fields cleared by the edits are sent as null.
+func (p *Patch) MergePatch(base *v1alpha1.TektonConfig) ([]byte, error) {
+	before, after, err := p.specs(base)
+	if err != nil {
+		return nil, err
+	}
+	diff := mergeDiff(before, after)
+	if len(diff) == 0 {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]any{"spec": diff})
+}
+
+// JSONPatch returns the JSON patch (RFC 6902) that turns base into the patched
+// TektonConfig. Lists are replaced as a whole.
+func (p *Patch) JSONPatch(base *v1alpha1.TektonConfig) ([]byte, error) {
+	before, after, err := p.specs(base)
+	if err != nil {
+		return nil, err
+	}
+	ops := JSONPatchOps("/spec", before, after)
+	if ops == nil {
+		ops = []map[string]any{}
+	}
+	return json.Marshal(ops)
+}
+
+// Validate checks the TektonConfig that results from applying the patch to
+// base against the TektonConfig CRD schema, reporting unknown fields as well.
+func (p *Patch) Validate(base *v1alpha1.TektonConfig) error {
+	_, after, err := p.specs(base)
+	if err != nil {
+		return err
+	}
+	return ValidateSpec(after)
+}
+
+// Apply reads TektonConfig name, validates the patched spec unless the patch
+// is Unchecked and sends the difference as a merge patch. The merge patch
+// carries whole lists, so a Param edit sends every spec.params entry with the
+// one it sets updated, rather than replacing the list with that entry. It
+// returns the updated TektonConfig, or the current one when the patch changes
+// nothing.
+func (p *Patch) Apply(ctx context.Context, client operatorv1alpha1.TektonConfigInterface, name string) (*v1alpha1.TektonConfig, error) {
+	tc, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TektonConfig %s: %w", name, err)
+	}
+	if !p.unchecked {
+		if err := p.Validate(tc); err != nil {
+			return nil, fmt.Errorf("invalid patch for TektonConfig %s: %w", name, err)
+		}
+	}
+	data, err := p.MergePatch(tc)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "{}" {
+		return tc, nil
+	}
+	tc, err = client.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch TektonConfig %s with %s: %w", name, data, err)
+	}
+	return tc, nil
+}
+
+// specs returns the spec of base and the patched spec as JSON objects.
+func (p *Patch) specs(base *v1alpha1.TektonConfig) (before, after map[string]any, err error) {
+	spec := v1alpha1.TektonConfigSpec{}
+	if base != nil {
+		spec = *base.Spec.DeepCopy()
+	}
+	if err := convert(spec, &before); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode TektonConfig spec: %w", err)
+	}
+	for _, edit := range p.edits {
+		edit(&spec)
+	}
+	if err := convert(spec, &after); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode patched TektonConfig spec: %w", err)
+	}
+	for _, e := range p.raw {
+		if err := e.apply(after); err != nil {
+			return nil, nil, err
+		}
+	}
+	return before, after, nil
+}
+
+func (e rawEdit) apply(spec map[string]any) error {
+	if len(e.fields) == 0 {
+		return fmt.Errorf("empty path in TektonConfig patch")
+	}
+	parent := spec
+	for _, f := range e.fields[:len(e.fields)-1] {
+		child, ok := parent[f].(map[string]any)
+		if !ok {
+			if e.remove {
+				return nil
+			}
+			child = map[string]any{}
+			parent[f] = child
+		}
+		parent = child
+	}
+	last := e.fields[len(e.fields)-1]
+	if e.remove {
+		delete(parent, last)
+		return nil
+	}
+	var value any
+	if err := convert(e.value, &value); err != nil {
+		return fmt.Errorf("failed to encode spec.%s: %w", strings.Join(e.fields, "."), err)
+	}
+	parent[last] = value
+	return nil
+}
+
+// setParam sets param name to value, appending it when missing.
+func setParam(params []v1alpha1.Param, name, value string) []v1alpha1.Param {
+	for i := range params {
+		if params[i].Name == name {
+			params[i].Value = value
+			return params
+		}
+	}
+	return append(params, v1alpha1.Param{Name: name, Value: value})
+}
+
+// mergeDiff returns the merge patch turning before into after: changed and new
+// fields with their new value, nested objects as their own diff and removed
+// fields as nil.
+func mergeDiff(before, after map[string]any) map[string]any {
+	diff := map[string]any{}
+	for key, value := range after {
+		old, ok := before[key]
+		oldMap, oldIsMap := old.(map[string]any)
+		newMap, newIsMap := value.(map[string]any)
+		switch {
+		case !ok:
+			diff[key] = value
+		case oldIsMap && newIsMap:
+			if sub := mergeDiff(oldMap, newMap); len(sub) > 0 {
+				diff[key] = sub
+			}
+		case !reflect.DeepEqual(old, value):
+			diff[key] = value
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			diff[key] = nil
+		}
+	}
+	return diff
+}
+
+// JSONPatchOps returns the JSON patch (RFC 6902) operations that turn the
+// object from into to, both below path, e.g. "/spec". Lists are replaced as a
+// whole. It works on any unstructured content, not only TektonConfig specs.
+func JSONPatchOps(path string, from, to map[string]any) []map[string]any {
+	return jsonPatchOps(path, from, mergeDiff(from, to))
+}
+
+// jsonPatchOps turns the merge patch diff of current into JSON patch
+// operations. Objects that are empty in current are replaced instead of
+// descended into, since the server may not store them at all.
+func jsonPatchOps(path string, current, diff map[string]any) []map[string]any {
+	var ops []map[string]any
+	for _, key := range slices.Sorted(maps.Keys(diff)) {
+		child := path + "/" + jsonPointerEscaper.Replace(key)
+		value := diff[key]
+		if value == nil {
+			ops = append(ops, map[string]any{"op": "remove", "path": child})
+			continue
+		}
+		currentMap, currentIsMap := current[key].(map[string]any)
+		diffMap, diffIsMap := value.(map[string]any)
+		if currentIsMap && diffIsMap && len(currentMap) > 0 {
+			ops = append(ops, jsonPatchOps(child, currentMap, diffMap)...)
+			continue
+		}
+		if diffIsMap {
+			// diff only holds the changes; the new object is current with them.
+			value = applyMergeDiff(currentMap, diffMap)
+		}
+		ops = append(ops, map[string]any{"op": "add", "path": child, "value": value})
+	}
+	return ops
+}
+
+// applyMergeDiff returns a copy of obj with the merge patch diff applied.
+func applyMergeDiff(obj, diff map[string]any) map[string]any {
+	out := maps.Clone(obj)
+	if out == nil {
+		out = map[string]any{}
+	}
+	for key, value := range diff {
+		sub, isMap := value.(map[string]any)
+		switch {
+		case value == nil:
+			delete(out, key)
+		case isMap:
+			old, _ := out[key].(map[string]any)
+			out[key] = applyMergeDiff(old, sub)
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// jsonPointerEscaper escapes a key for use in a JSON pointer (RFC 6901).
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// convert round-trips in through JSON into out.
+func convert(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
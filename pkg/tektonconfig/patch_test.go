@@ -0,0 +1,190 @@
+package tektonconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func baseTektonConfig() *v1alpha1.TektonConfig {
+	keep := uint(100)
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultName},
+		Spec: v1alpha1.TektonConfigSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
+			Addon: v1alpha1.Addon{Params: []v1alpha1.Param{
+				{Name: "resolverTasks", Value: "true"},
+				{Name: "resolverStepActions", Value: "true"},
+				{Name: "pipelineTemplates", Value: "true"},
+				{Name: "communityResolverTasks", Value: "true"},
+			}},
+			Pruner: v1alpha1.Prune{
+				Resources: []string{"pipelinerun"},
+				Keep:      &keep,
+				Schedule:  "0 8 * * *",
+			},
+			Params: []v1alpha1.Param{
+				{Name: "createRbacResource", Value: "true"},
+				{Name: "createCABundleConfigMaps", Value: "true"},
+			},
+		},
+	}
+	tc.SetDefaults(context.Background())
+	return tc
+}
+
+func decode(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return v
+}
+
+func TestMergePatch(t *testing.T) {
+	keepSince := uint(10)
+	patch := NewPatch().
+		Param("createRbacResource", "false").
+		AddonParam("resolverTasks", "false").
+		Pruner(func(p *v1alpha1.Prune) {
+			p.Keep = nil
+			p.KeepSince = &keepSince
+		}).
+		Set("300", "tektonpruner", "global-config", "namespaces", "dev", "ttlSecondsAfterFinished")
+
+	data, err := patch.MergePatch(baseTektonConfig())
+	if err != nil {
+		t.Fatalf("MergePatch() error = %v", err)
+	}
+	want := decode(t, []byte(`{"spec":{
+		"params":[{"name":"createRbacResource","value":"false"},{"name":"createCABundleConfigMaps","value":"true"}],
+		"addon":{"params":[
+			{"name":"resolverTasks","value":"false"},
+			{"name":"resolverStepActions","value":"true"},
+			{"name":"pipelineTemplates","value":"true"},
+			{"name":"communityResolverTasks","value":"true"}
+		]},
+		"pruner":{"keep":null,"keep-since":10},
+		"tektonpruner":{"global-config":{"namespaces":{"dev":{"ttlSecondsAfterFinished":"300"}}}}
+	}}`))
+	if got := decode(t, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergePatch() = %s, want %v", data, want)
+	}
+}
+
+func TestMergePatchWithoutChanges(t *testing.T) {
+	data, err := NewPatch().Param("createRbacResource", "true").MergePatch(baseTektonConfig())
+	if err != nil {
+		t.Fatalf("MergePatch() error = %v", err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("MergePatch() = %s, want {}", data)
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	patch := NewPatch().
+		Pruner(func(p *v1alpha1.Prune) {
+			p.Keep = nil
+			p.Schedule = "*/1 * * * *"
+		}).
+		Chain(func(c *v1alpha1.Chain) { c.ArtifactsTaskRunFormat = "slsa/v1" }).
+		Remove("params")
+
+	data, err := patch.JSONPatch(baseTektonConfig())
+	if err != nil {
+		t.Fatalf("JSONPatch() error = %v", err)
+	}
+	want := decode(t, []byte(`[
+		{"op":"add","path":"/spec/chain/artifacts.taskrun.format","value":"slsa/v1"},
+		{"op":"remove","path":"/spec/params"},
+		{"op":"remove","path":"/spec/pruner/keep"},
+		{"op":"add","path":"/spec/pruner/schedule","value":"*/1 * * * *"}
+	]`))
+	if got := decode(t, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONPatch() = %s, want %v", data, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	client := operatorfake.NewSimpleClientset(baseTektonConfig()).OperatorV1alpha1().TektonConfigs()
+
+	tc, err := NewPatch().
+		Param("createCABundleConfigMaps", "false").
+		Pruner(func(p *v1alpha1.Prune) { p.Resources = []string{"pipelinerun", "taskrun"} }).
+		Apply(ctx, client, DefaultName)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := tc.Spec.Params[1]; got.Name != "createCABundleConfigMaps" || got.Value != "false" {
+		t.Errorf("Apply() params[1] = %+v, want createCABundleConfigMaps=false", got)
+	}
+	if got := tc.Spec.Params[0]; got.Value != "true" {
+		t.Errorf("Apply() changed params[0] to %+v", got)
+	}
+	if got := tc.Spec.Pruner.Resources; !reflect.DeepEqual(got, []string{"pipelinerun", "taskrun"}) {
+		t.Errorf("Apply() pruner resources = %v", got)
+	}
+	if tc.Spec.Pruner.Keep == nil || *tc.Spec.Pruner.Keep != 100 {
+		t.Errorf("Apply() changed pruner keep to %v", tc.Spec.Pruner.Keep)
+	}
+}
+
+func TestApplyRejectsInvalidPatch(t *testing.T) {
+	ctx := context.Background()
+	fake := operatorfake.NewSimpleClientset(baseTektonConfig())
+
+	_, err := NewPatch().Set("two", "pruner", "keep").Apply(ctx, fake.OperatorV1alpha1().TektonConfigs(), DefaultName)
+	if err == nil {
+		t.Fatal("Apply() error = nil, want a schema error")
+	}
+	for _, action := range fake.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("Apply() sent a patch despite the schema error: %v", err)
+		}
+	}
+}
+
+func TestApplyUnchecked(t *testing.T) {
+	ctx := context.Background()
+	fake := operatorfake.NewSimpleClientset(baseTektonConfig())
+	fake.PrependReactor("patch", "tektonconfigs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New(`admission webhook "validation.webhook.operator.tekton.dev" denied the request`)
+	})
+
+	_, err := NewPatch().Set("two", "pruner", "keep").Unchecked().Apply(ctx, fake.OperatorV1alpha1().TektonConfigs(), DefaultName)
+	if err == nil || !strings.Contains(err.Error(), "denied the request") {
+		t.Fatalf("Apply() error = %v, want the cluster to reject the patch", err)
+	}
+}
+
+func TestJSONPatchOps(t *testing.T) {
+	from := map[string]any{
+		"a":   int64(1),
+		"b":   map[string]any{"c": int64(1), "d": int64(2)},
+		"x/y": "z",
+	}
+	to := map[string]any{"b": map[string]any{"c": int64(3)}, "e": []any{"f"}}
+	got := JSONPatchOps("/spec", from, to)
+	want := []map[string]any{
+		{"op": "remove", "path": "/spec/a"},
+		{"op": "add", "path": "/spec/b/c", "value": int64(3)},
+		{"op": "remove", "path": "/spec/b/d"},
+		{"op": "add", "path": "/spec/e", "value": []any{"f"}},
+		{"op": "remove", "path": "/spec/x~1y"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONPatchOps() = %v, want %v", got, want)
+	}
+}
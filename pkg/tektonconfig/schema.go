@@ -0,0 +1,130 @@
+package tektonconfig
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+)
+
+// crdYAML is config/base/generated-crds/operator.tekton.dev_tektonconfigs.yaml
+// of the tektoncd/operator version in go.mod. Copy it again when bumping the
+// operator.
+//
+//go:embed operator.tekton.dev_tektonconfigs.yaml
+var crdYAML []byte
+
+// specSchema returns the OpenAPI schema of the v1alpha1 TektonConfig spec.
+var specSchema = sync.OnceValues(func() (*spec.Schema, error) {
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					OpenAPIV3Schema *spec.Schema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(crdYAML, &crd); err != nil {
+		return nil, fmt.Errorf("failed to parse TektonConfig CRD: %w", err)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name != "v1alpha1" || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		s, ok := v.Schema.OpenAPIV3Schema.Properties["spec"]
+		if !ok {
+			break
+		}
+		return &s, nil
+	}
+	return nil, errors.New("TektonConfig CRD has no v1alpha1 spec schema")
+})
+
+// ValidateSpec checks a TektonConfig spec, as decoded from JSON, against the
+// TektonConfig CRD schema. Besides the schema itself it reports fields the
+// schema does not know, which the API server would silently drop.
+func ValidateSpec(spec map[string]any) error {
+	schema, err := specSchema()
+	if err != nil {
+		return err
+	}
+	// The API server drops nulls of non-nullable fields before validating.
+	spec = dropNulls(spec)
+	var errs []error
+	for _, path := range unknownFields("spec", spec, schema) {
+		errs = append(errs, fmt.Errorf("%s: unknown field", path))
+	}
+	result := validate.NewSchemaValidator(schema, nil, "spec", strfmt.Default).Validate(spec)
+	errs = append(errs, result.Errors...)
+	return errors.Join(errs...)
+}
+
+// unknownFields lists the paths of fields below path missing from schema,
+// skipping subtrees that preserve unknown fields.
+func unknownFields(path string, value any, schema *spec.Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	if preserve, _ := schema.Extensions.GetBool("x-kubernetes-preserve-unknown-fields"); preserve {
+		return nil
+	}
+	var unknown []string
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			child := path + "." + key
+			if prop, ok := schema.Properties[key]; ok {
+				unknown = append(unknown, unknownFields(child, v[key], &prop)...)
+				continue
+			}
+			if schema.AdditionalProperties != nil {
+				unknown = append(unknown, unknownFields(child, v[key], schema.AdditionalProperties.Schema)...)
+				continue
+			}
+			if len(schema.Properties) > 0 {
+				unknown = append(unknown, child)
+			}
+		}
+	case []any:
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			unknown = append(unknown, unknownFields(fmt.Sprintf("%s[%d]", path, i), item, schema.Items.Schema)...)
+		}
+	}
+	return unknown
+}
+
+// dropNulls returns a copy of obj without null fields.
+func dropNulls(obj map[string]any) map[string]any {
+	out := make(map[string]any, len(obj))
+	for key, value := range obj {
+		switch v := value.(type) {
+		case nil:
+		case map[string]any:
+			out[key] = dropNulls(v)
+		case []any:
+			items := make([]any, len(v))
+			for i, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					item = dropNulls(m)
+				}
+				items[i] = item
+			}
+			out[key] = items
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
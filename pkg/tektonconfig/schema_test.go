@@ -0,0 +1,48 @@
+package tektonconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   *Patch
+		wantErr string
+	}{{
+		name: "typed fields",
+		patch: NewPatch().
+			Param("createRbacResource", "false").
+			Chain(func(c *v1alpha1.Chain) { c.TransparencyConfigEnabled = "true" }).
+			Set(nil, "tektonpruner", "global-config", "ttlSecondsAfterFinished"),
+	}, {
+		name:    "wrong type",
+		patch:   NewPatch().Set("two", "pruner", "keep"),
+		wantErr: "spec.pruner.keep",
+	}, {
+		name:    "unknown field",
+		patch:   NewPatch().Set("x", "pruner", "keep-until"),
+		wantErr: "spec.pruner.keep-until: unknown field",
+	}, {
+		name:    "unknown field in list item",
+		patch:   NewPatch().Set([]any{map[string]any{"name": "a", "vaule": "b"}}, "params"),
+		wantErr: "spec.params[0].vaule: unknown field",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patch.Validate(baseTektonConfig())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
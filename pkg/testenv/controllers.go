@@ -0,0 +1,119 @@
+package testenv
+
+import (
+	"context"
+	"fmt"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/apis"
+)
+
+// MarkPipelineRunSucceeded sets the Succeeded condition of a PipelineRun to
+// True, as the Tekton controller does when every task finished.
+func (e *Env) MarkPipelineRunSucceeded(ctx context.Context, namespace, name string) error {
+	return e.finishPipelineRun(ctx, namespace, name, corev1.ConditionTrue, pipelinev1.PipelineRunReasonSuccessful.String(), "Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0")
+}
+
+// MarkPipelineRunFailed sets the Succeeded condition of a PipelineRun to False
+// with the given reason and message.
+func (e *Env) MarkPipelineRunFailed(ctx context.Context, namespace, name, reason, message string) error {
+	return e.finishPipelineRun(ctx, namespace, name, corev1.ConditionFalse, reason, message)
+}
+
+func (e *Env) finishPipelineRun(ctx context.Context, namespace, name string, status corev1.ConditionStatus, reason, message string) error {
+	client := e.Tekton.TektonV1().PipelineRuns(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pr, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		now := metav1.Now()
+		if pr.Status.StartTime == nil {
+			pr.Status.StartTime = &now
+		}
+		pr.Status.CompletionTime = &now
+		pr.Status.SetCondition(&apis.Condition{
+			Type:    apis.ConditionSucceeded,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+		_, err = client.UpdateStatus(ctx, pr, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update status of pipelinerun %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// MarkTaskRunSucceeded sets the Succeeded condition of a TaskRun to True.
+func (e *Env) MarkTaskRunSucceeded(ctx context.Context, namespace, name string) error {
+	client := e.Tekton.TektonV1().TaskRuns(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tr, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		now := metav1.Now()
+		if tr.Status.StartTime == nil {
+			tr.Status.StartTime = &now
+		}
+		tr.Status.CompletionTime = &now
+		tr.Status.SetCondition(&apis.Condition{
+			Type:    apis.ConditionSucceeded,
+			Status:  corev1.ConditionTrue,
+			Reason:  pipelinev1.TaskRunReasonSuccessful.String(),
+			Message: "All Steps have completed executing",
+		})
+		_, err = client.UpdateStatus(ctx, tr, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update status of taskrun %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// SetDeploymentAvailable reports every desired replica of a Deployment as
+// ready and available, as the deployment controller does once its pods are up.
+func (e *Env) SetDeploymentAvailable(ctx context.Context, namespace, name string) error {
+	client := e.KubeClient.Kube.AppsV1().Deployments(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		d, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		now := metav1.Now()
+		d.Status = appsv1.DeploymentStatus{
+			ObservedGeneration:  d.Generation,
+			Replicas:            replicas,
+			UpdatedReplicas:     replicas,
+			ReadyReplicas:       replicas,
+			AvailableReplicas:   replicas,
+			UnavailableReplicas: 0,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:               appsv1.DeploymentAvailable,
+				Status:             corev1.ConditionTrue,
+				Reason:             "MinimumReplicasAvailable",
+				Message:            "Deployment has minimum availability.",
+				LastUpdateTime:     now,
+				LastTransitionTime: now,
+			}},
+		}
+		_, err = client.UpdateStatus(ctx, d, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update status of deployment %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package testenv boots a local kube-apiserver and etcd through controller-runtime's
+// envtest with the Tekton Pipelines, Triggers, Pipelines as Code, operator, OLM
+// and OpenShift Route/config CRDs installed, and builds a clients.Clients against
+// it. Nothing reconciles on that API server, so the helpers in controllers.go
+// stand in for the controllers a spec would normally wait on.
+//
+// The API server binaries are not vendored. Install them once with
+//
+//	go run sigs.k8s.io/controller-runtime/tools/setup-envtest@latest use -p path
+//
+// and export KUBEBUILDER_ASSETS to the printed directory; Start reports
+// ErrNoAssets and StartT skips the test when they are missing.
+package testenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+)
+
+// DefaultNamespace is the namespace Start creates and scopes the namespaced
+// Tekton clients to.
+const DefaultNamespace = "releasetest"
+
+// defaultAssetsDir is where envtest looks for binaries without KUBEBUILDER_ASSETS.
+const defaultAssetsDir = "/usr/local/kubebuilder/bin"
+
+// ErrNoAssets is returned by Start when the kube-apiserver and etcd binaries
+// cannot be found.
+var ErrNoAssets = errors.New("envtest binaries not found, set KUBEBUILDER_ASSETS (see setup-envtest)")
+
+// crdSource lists CRD manifests shipped inside a Go module this repo already
+// depends on, relative to the module root. Using the module cache keeps the
+// CRDs in step with the client versions in go.mod.
+type crdSource struct {
+	module string
+	paths  []string
+}
+
+var crdSources = []crdSource{{
+	module: "github.com/tektoncd/pipeline",
+	paths:  []string{"config/300-crds"},
+}, {
+	module: "github.com/tektoncd/triggers",
+	paths: []string{
+		"config/300-clusterinterceptor.yaml",
+		"config/300-clustertriggerbinding.yaml",
+		"config/300-eventlistener.yaml",
+		"config/300-interceptor.yaml",
+		"config/300-trigger.yaml",
+		"config/300-triggerbinding.yaml",
+		"config/300-triggertemplate.yaml",
+	},
+}, {
+	module: "github.com/openshift-pipelines/pipelines-as-code",
+	paths:  []string{"config/300-repositories.yaml"},
+}, {
+	module: "github.com/tektoncd/operator",
+	paths:  []string{"config/base/generated-crds"},
+}, {
+	module: "github.com/operator-framework/api",
+	paths:  []string{"crds"},
+}, {
+	module: "github.com/openshift/api",
+	paths: []string{
+		"route/v1/zz_generated.crd-manifests/routes.crd.yaml",
+		"config/v1/zz_generated.crd-manifests/0000_03_config-operator_01_proxies.crd.yaml",
+		"config/v1/zz_generated.crd-manifests/0000_00_cluster-version-operator_01_clusterversions-Default.crd.yaml",
+		"console/v1/zz_generated.crd-manifests/00_consoleclidownloads.crd.yaml",
+	},
+}}
+
+// Env is a running envtest API server with the clients of this repo wired to it.
+type Env struct {
+	*clients.Clients
+	Namespace string
+
+	env *envtest.Environment
+}
+
+// Start boots the API server, installs the CRDs and creates namespace, which
+// defaults to DefaultNamespace. Call Stop when done.
+func Start(namespace string) (*Env, error) {
+	if !assetsAvailable() {
+		return nil, ErrNoAssets
+	}
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	paths, err := crdPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     paths,
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start envtest: %w", err)
+	}
+	e := &Env{Namespace: namespace, env: env}
+	e.Clients, err = clients.NewClientsForConfig(cfg, namespace)
+	if err != nil {
+		return nil, errors.Join(err, env.Stop())
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	_, err = e.KubeClient.Kube.CoreV1().Namespaces().Create(e.Ctx, ns, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Join(fmt.Errorf("failed to create namespace %s: %w", namespace, err), env.Stop())
+	}
+	return e, nil
+}
+
+// StartT starts an Env for a single test, skipping it when the envtest binaries
+// are missing and stopping the API server when the test ends.
+func StartT(t testing.TB, namespace string) *Env {
+	t.Helper()
+	e, err := Start(namespace)
+	if errors.Is(err, ErrNoAssets) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := e.Stop(); err != nil {
+			t.Errorf("failed to stop envtest: %v", err)
+		}
+	})
+	return e
+}
+
+// Stop shuts the API server and etcd down.
+func (e *Env) Stop() error {
+	return e.env.Stop()
+}
+
+// assetsAvailable reports whether envtest will find its binaries, either through
+// KUBEBUILDER_ASSETS or in the default install directory.
+func assetsAvailable() bool {
+	dir := os.Getenv("KUBEBUILDER_ASSETS")
+	if dir == "" {
+		dir = defaultAssetsDir
+	}
+	for _, bin := range []string{"kube-apiserver", "etcd"} {
+		if _, err := os.Stat(filepath.Join(dir, bin)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// crdPaths resolves crdSources to absolute paths with `go list -m`, which
+// honours go.mod replaces and the module cache location.
+func crdPaths() ([]string, error) {
+	args := []string{"list", "-m", "-f", "{{.Dir}}"}
+	for _, src := range crdSources {
+		args = append(args, src.module)
+	}
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to locate CRD modules: %w: %s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("failed to locate CRD modules: %w", err)
+	}
+	dirs := strings.Fields(string(out))
+	if len(dirs) != len(crdSources) {
+		return nil, fmt.Errorf("failed to locate CRD modules, run go mod download: got %q", dirs)
+	}
+
+	var paths []string
+	for i, src := range crdSources {
+		for _, p := range src.paths {
+			paths = append(paths, filepath.Join(dirs[i], filepath.FromSlash(p)))
+		}
+	}
+	return paths, nil
+}
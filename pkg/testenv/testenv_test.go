@@ -0,0 +1,77 @@
+package testenv
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/k8s"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/wait"
+)
+
+func TestCRDPathsExist(t *testing.T) {
+	paths, err := crdPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("CRD path %s: %v", p, err)
+		}
+	}
+}
+
+func TestWaitForPipelineRunState(t *testing.T) {
+	e := StartT(t, "")
+	ctx := context.Background()
+
+	pr := &pipelinev1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: e.Namespace},
+		Spec:       pipelinev1.PipelineRunSpec{PipelineRef: &pipelinev1.PipelineRef{Name: "build"}},
+	}
+	if _, err := e.PipelineRunClient.Create(ctx, pr, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(time.Second)
+		if err := e.MarkPipelineRunSucceeded(ctx, e.Namespace, pr.Name); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := wait.WaitForPipelineRunState(e.Clients, pr.Name, wait.PipelineRunSucceed(pr.Name), "PipelineRunSucceeded"); err != nil {
+		t.Fatalf("WaitForPipelineRunState() error = %v", err)
+	}
+}
+
+func TestWaitForDeployment(t *testing.T) {
+	e := StartT(t, "")
+	ctx := context.Background()
+
+	labels := map[string]string{"app": "controller"}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: e.Namespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: new(int32(2)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "controller", Image: "controller"}}},
+			},
+		},
+	}
+	if _, err := e.KubeClient.Kube.AppsV1().Deployments(e.Namespace).Create(ctx, d, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetDeploymentAvailable(ctx, e.Namespace, d.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := k8s.WaitForDeployment(ctx, e.KubeClient.Kube, e.Namespace, d.Name, 2, 100*time.Millisecond, 10*time.Second); err != nil {
+		t.Fatalf("WaitForDeployment() error = %v", err)
+	}
+}
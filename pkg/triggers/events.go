@@ -0,0 +1,462 @@
+package triggers
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	bbtypes "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Provider is a git hosting service whose webhooks NewEvent can synthesize. The
+// names match the interceptor argument of MockPostEvent and BuildHeaders.
+type Provider string
+
+const (
+	// GitHub events are signed with X-Hub-Signature-256.
+	GitHub Provider = "github"
+	// GitLab events carry the secret in X-GitLab-Token.
+	GitLab Provider = "gitlab"
+	// Bitbucket events follow Bitbucket Server (Data Center).
+	Bitbucket Provider = "bitbucket"
+)
+
+// EventKind is a provider-neutral webhook event.
+type EventKind string
+
+const (
+	// Push is a push of Branch.
+	Push EventKind = "push"
+	// TagPush is a push of Tag.
+	TagPush EventKind = "tag_push"
+	// PullRequest is a pull request from Branch into BaseBranch; Action says
+	// what happened to it, e.g. opened or synchronize.
+	PullRequest EventKind = "pull_request"
+	// MergeRequest is the GitLab name of a PullRequest.
+	MergeRequest = PullRequest
+	// IssueComment is a Comment on a pull request.
+	IssueComment EventKind = "issue_comment"
+	// PullRequestReview is a review of a pull request with ReviewState.
+	PullRequestReview EventKind = "pull_request_review"
+)
+
+// EventParams are the typed fields of a synthesized event. Only Repo is
+// required; NewEvent fills in the rest and returns them on Event.Params so specs
+// can assert on the values a TriggerBinding extracts.
+type EventParams struct {
+	// Repo is the owner/name path of the repository. On Bitbucket the owner is
+	// the project key.
+	Repo string
+	// Host is the base URL of the provider, e.g. https://github.com.
+	Host string
+	// Branch is the pushed branch or the head branch of a pull request. Defaults to main.
+	Branch string
+	// BaseBranch is the target branch of a pull request. Defaults to main.
+	BaseBranch string
+	// Tag is the pushed tag of a TagPush.
+	Tag string
+	// SHA is the pushed or head commit. Defaults to a random commit ID.
+	SHA string
+	// BeforeSHA is the commit the ref pointed to before a push.
+	BeforeSHA string
+	// Author is the login of the user who pushed, opened, commented or reviewed.
+	Author string
+	// Labels are the labels of a pull request.
+	Labels []string
+	// Number is the pull request number. Defaults to 1.
+	Number int
+	// Title is the pull request title or the head commit message.
+	Title string
+	// Action overrides the provider's default action, e.g. synchronize instead
+	// of opened on GitHub, update instead of open on GitLab.
+	Action string
+	// Comment is the body of an IssueComment or PullRequestReview.
+	Comment string
+	// ReviewState is the state of a PullRequestReview. Defaults to approved.
+	ReviewState string
+}
+
+// Event is a synthesized webhook, ready for MockPostGeneratedEvent.
+type Event struct {
+	Provider Provider
+	Kind     EventKind
+	// Type is the event type header value in the form BuildHeaders takes it,
+	// e.g. pull_request for GitHub or Merge Request Hook for GitLab.
+	Type    string
+	Params  EventParams
+	Payload []byte
+}
+
+// zeroSHA is the before commit of a newly created ref.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+var defaultHosts = map[Provider]string{
+	GitHub:    "https://github.com",
+	GitLab:    "https://gitlab.com",
+	Bitbucket: "http://localhost:7990",
+}
+
+// NewEvent synthesizes the webhook provider sends for kind with the typed params.
+// Payloads are built from the provider's own webhook types (go-github, go-gitlab
+// and the Bitbucket Data Center types of Pipelines as Code), so the fields
+// TriggerBindings and ClusterTriggerBindings read are where the provider puts them.
+func NewEvent(provider Provider, kind EventKind, params EventParams) (*Event, error) {
+	host, ok := defaultHosts[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported event provider %q", provider)
+	}
+	owner, name, ok := strings.Cut(params.Repo, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("event repo %q is not owner/name", params.Repo)
+	}
+	if kind == TagPush && params.Tag == "" {
+		return nil, fmt.Errorf("a %s event needs a tag", kind)
+	}
+	params.Host = strings.TrimSuffix(cmp.Or(params.Host, host), "/")
+	params.Branch = cmp.Or(params.Branch, "main")
+	params.BaseBranch = cmp.Or(params.BaseBranch, "main")
+	params.SHA = cmp.Or(params.SHA, randomSHA())
+	params.BeforeSHA = cmp.Or(params.BeforeSHA, zeroSHA)
+	params.Author = cmp.Or(params.Author, "release-tests")
+	params.Number = cmp.Or(params.Number, 1)
+	params.Title = cmp.Or(params.Title, "Release tests change")
+	if kind == PullRequestReview {
+		params.ReviewState = cmp.Or(params.ReviewState, "approved")
+	}
+
+	e := &Event{Provider: provider, Kind: kind, Params: params}
+	var (
+		payload any
+		err     error
+	)
+	switch provider {
+	case GitHub:
+		e.Type, payload, err = githubEvent(kind, params, owner, name)
+	case GitLab:
+		e.Type, payload, err = gitlabEvent(kind, params, owner, name)
+	case Bitbucket:
+		e.Type, payload, err = bitbucketEvent(kind, params, owner, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e.Payload, err = json.Marshal(payload); err != nil {
+		return nil, fmt.Errorf("failed to marshal %s %s event: %w", provider, kind, err)
+	}
+	return e, nil
+}
+
+// MustNewEvent is NewEvent for specs; it panics on invalid params.
+func MustNewEvent(provider Provider, kind EventKind, params EventParams) *Event {
+	e, err := NewEvent(provider, kind, params)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// randomSHA returns a random 40 character commit ID.
+func randomSHA() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ref returns the full ref a push of params updates.
+func (p EventParams) ref(kind EventKind) string {
+	if kind == TagPush {
+		return "refs/tags/" + p.Tag
+	}
+	return "refs/heads/" + p.Branch
+}
+
+func githubEvent(kind EventKind, p EventParams, owner, name string) (string, any, error) {
+	htmlURL := fmt.Sprintf("%s/%s/%s", p.Host, owner, name)
+	user := &github.User{Login: new(p.Author), Type: new("User")}
+	repo := &github.Repository{
+		Name:          new(name),
+		FullName:      new(p.Repo),
+		Owner:         &github.User{Login: new(owner)},
+		HTMLURL:       new(htmlURL),
+		URL:           new(htmlURL),
+		CloneURL:      new(htmlURL + ".git"),
+		DefaultBranch: new(p.BaseBranch),
+	}
+	var labels []*github.Label
+	for _, label := range p.Labels {
+		labels = append(labels, &github.Label{Name: new(label)})
+	}
+	pr := &github.PullRequest{
+		Number:  new(p.Number),
+		State:   new("open"),
+		Title:   new(p.Title),
+		User:    user,
+		Labels:  labels,
+		HTMLURL: new(fmt.Sprintf("%s/pull/%d", htmlURL, p.Number)),
+		Head:    &github.PullRequestBranch{Ref: new(p.Branch), SHA: new(p.SHA), Repo: repo, User: user},
+		Base:    &github.PullRequestBranch{Ref: new(p.BaseBranch), SHA: new(p.BeforeSHA), Repo: repo},
+	}
+
+	switch kind {
+	case Push, TagPush:
+		commit := &github.HeadCommit{
+			ID:        new(p.SHA),
+			Message:   new(p.Title),
+			URL:       new(htmlURL + "/commit/" + p.SHA),
+			Timestamp: &github.Timestamp{Time: time.Now()},
+			Author:    &github.CommitAuthor{Name: new(p.Author), Login: new(p.Author)},
+		}
+		return "push", &github.PushEvent{
+			Ref:        new(p.ref(kind)),
+			Before:     new(p.BeforeSHA),
+			After:      new(p.SHA),
+			Created:    new(p.BeforeSHA == zeroSHA),
+			HeadCommit: commit,
+			Commits:    []*github.HeadCommit{commit},
+			Repo: &github.PushEventRepository{
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				Owner:         repo.Owner,
+				HTMLURL:       repo.HTMLURL,
+				URL:           repo.URL,
+				CloneURL:      repo.CloneURL,
+				DefaultBranch: repo.DefaultBranch,
+			},
+			Pusher: &github.CommitAuthor{Name: new(p.Author)},
+			Sender: user,
+		}, nil
+	case PullRequest:
+		return "pull_request", &github.PullRequestEvent{
+			Action:      new(cmp.Or(p.Action, "opened")),
+			Number:      new(p.Number),
+			PullRequest: pr,
+			Repo:        repo,
+			Sender:      user,
+		}, nil
+	case IssueComment:
+		return "issue_comment", &github.IssueCommentEvent{
+			Action: new(cmp.Or(p.Action, "created")),
+			Issue: &github.Issue{
+				Number:           new(p.Number),
+				Title:            new(p.Title),
+				State:            new("open"),
+				User:             user,
+				Labels:           labels,
+				PullRequestLinks: &github.PullRequestLinks{HTMLURL: pr.HTMLURL},
+			},
+			Comment: &github.IssueComment{Body: new(p.Comment), User: user},
+			Repo:    repo,
+			Sender:  user,
+		}, nil
+	case PullRequestReview:
+		return "pull_request_review", &github.PullRequestReviewEvent{
+			Action: new(cmp.Or(p.Action, "submitted")),
+			Review: &github.PullRequestReview{
+				Body:     new(p.Comment),
+				State:    new(p.ReviewState),
+				User:     user,
+				CommitID: new(p.SHA),
+			},
+			PullRequest: pr,
+			Repo:        repo,
+			Sender:      user,
+		}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported github event %q", kind)
+}
+
+func gitlabEvent(kind EventKind, p EventParams, owner, name string) (string, any, error) {
+	webURL := fmt.Sprintf("%s/%s/%s", p.Host, owner, name)
+	host := strings.TrimPrefix(strings.TrimPrefix(p.Host, "https://"), "http://")
+	repo := &gitlab.Repository{
+		Name:              name,
+		WebURL:            webURL,
+		GitHTTPURL:        webURL + ".git",
+		GitSSHURL:         fmt.Sprintf("git@%s:%s.git", host, p.Repo),
+		Namespace:         owner,
+		PathWithNamespace: p.Repo,
+		DefaultBranch:     p.BaseBranch,
+		Homepage:          webURL,
+		URL:               webURL + ".git",
+		HTTPURL:           webURL + ".git",
+		Visibility:        gitlab.PublicVisibility,
+	}
+	user := &gitlab.EventUser{Name: p.Author, Username: p.Author}
+	var labels []*gitlab.EventLabel
+	for _, label := range p.Labels {
+		labels = append(labels, &gitlab.EventLabel{Title: label, Type: "ProjectLabel"})
+	}
+
+	switch kind {
+	case Push:
+		e := &gitlab.PushEvent{
+			ObjectKind:        "push",
+			EventName:         "push",
+			Before:            p.BeforeSHA,
+			After:             p.SHA,
+			Ref:               p.ref(kind),
+			CheckoutSHA:       p.SHA,
+			UserName:          p.Author,
+			UserUsername:      p.Author,
+			Repository:        repo,
+			TotalCommitsCount: 1,
+		}
+		return "Push Hook", e, copyProject(repo, &e.Project)
+	case TagPush:
+		e := &gitlab.TagEvent{
+			ObjectKind:        "tag_push",
+			EventName:         "tag_push",
+			Before:            p.BeforeSHA,
+			After:             p.SHA,
+			Ref:               p.ref(kind),
+			CheckoutSHA:       p.SHA,
+			UserName:          p.Author,
+			UserUsername:      p.Author,
+			Repository:        repo,
+			TotalCommitsCount: 1,
+		}
+		return "Tag Push Hook", e, copyProject(repo, &e.Project)
+	case PullRequest, PullRequestReview:
+		action := cmp.Or(p.Action, "open")
+		if kind == PullRequestReview {
+			action = cmp.Or(p.Action, p.ReviewState)
+		}
+		e := &gitlab.MergeEvent{ObjectKind: "merge_request", EventType: "merge_request", User: user, Repository: repo, Labels: labels}
+		attrs := &e.ObjectAttributes
+		attrs.IID = p.Number
+		attrs.Title = p.Title
+		attrs.State = "opened"
+		attrs.Action = action
+		attrs.SourceBranch = p.Branch
+		attrs.TargetBranch = p.BaseBranch
+		attrs.URL = fmt.Sprintf("%s/-/merge_requests/%d", webURL, p.Number)
+		attrs.Source = repo
+		attrs.Target = repo
+		attrs.Labels = labels
+		attrs.LastCommit.ID = p.SHA
+		attrs.LastCommit.Message = p.Title
+		attrs.LastCommit.URL = webURL + "/-/commit/" + p.SHA
+		attrs.LastCommit.Author.Name = p.Author
+		return "Merge Request Hook", e, copyProject(repo, &e.Project)
+	case IssueComment:
+		e := &gitlab.MergeCommentEvent{ObjectKind: "note", EventType: "note", User: user, Repository: repo}
+		e.ObjectAttributes.Note = p.Comment
+		e.ObjectAttributes.NoteableType = "MergeRequest"
+		e.ObjectAttributes.CommitID = p.SHA
+		e.ObjectAttributes.URL = fmt.Sprintf("%s/-/merge_requests/%d", webURL, p.Number)
+		e.MergeRequest.IID = p.Number
+		e.MergeRequest.Title = p.Title
+		e.MergeRequest.State = "opened"
+		e.MergeRequest.SourceBranch = p.Branch
+		e.MergeRequest.TargetBranch = p.BaseBranch
+		e.MergeRequest.LastCommit.ID = p.SHA
+		return "Note Hook", e, copyProject(repo, &e.Project)
+	}
+	return "", nil, fmt.Errorf("unsupported gitlab event %q", kind)
+}
+
+// copyProject fills the anonymous project struct of a go-gitlab event, whose
+// JSON fields are a subset of those of a Repository.
+func copyProject(repo *gitlab.Repository, project any) error {
+	b, err := json.Marshal(repo)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, project)
+}
+
+func bitbucketEvent(kind EventKind, p EventParams, project, slug string) (string, any, error) {
+	repo := bbtypes.Repository{
+		Slug:    slug,
+		Name:    slug,
+		ScmID:   "git",
+		State:   "AVAILABLE",
+		Project: &bbtypes.Project{Key: strings.ToUpper(project), Name: project},
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(p.Host, "https://"), "http://")
+	repo.Links = &struct {
+		Clone []bbtypes.CloneLink `json:"clone,omitempty"`
+		Self  []bbtypes.SelfLink  `json:"self,omitempty"`
+	}{
+		Clone: []bbtypes.CloneLink{
+			{Href: fmt.Sprintf("%s/scm/%s/%s.git", p.Host, project, slug), Name: "http"},
+			{Href: fmt.Sprintf("ssh://git@%s/%s/%s.git", host, project, slug), Name: "ssh"},
+		},
+		Self: []bbtypes.SelfLink{{Href: fmt.Sprintf("%s/projects/%s/repos/%s/browse", p.Host, strings.ToUpper(project), slug)}},
+	}
+	actor := bbtypes.UserWithLinks{Name: p.Author, Slug: p.Author, DisplayName: p.Author, Active: true, Type: "NORMAL"}
+	date := time.Now().Format("2006-01-02T15:04:05-0700")
+
+	switch kind {
+	case Push, TagPush:
+		refType := "BRANCH"
+		displayID := p.Branch
+		if kind == TagPush {
+			refType, displayID = "TAG", p.Tag
+		}
+		changeType := "UPDATE"
+		if p.BeforeSHA == zeroSHA {
+			changeType = "ADD"
+		}
+		ref := p.ref(kind)
+		return "refs_changed", &bitbucketPushEvent{
+			PushRequestEvent: bbtypes.PushRequestEvent{
+				EventKey:   "repo:refs_changed",
+				Actor:      actor,
+				Repository: repo,
+				Changes: []bbtypes.PushRequestEventChange{{
+					Ref:      bbtypes.Ref{ID: ref, DisplayID: displayID, Type: refType},
+					RefID:    ref,
+					FromHash: p.BeforeSHA,
+					ToHash:   p.SHA,
+					Type:     changeType,
+				}},
+			},
+			Date: date,
+		}, nil
+	case PullRequest, IssueComment, PullRequestReview:
+		pr := bbtypes.PullRequest{
+			ID:      p.Number,
+			Title:   p.Title,
+			State:   "OPEN",
+			Open:    true,
+			FromRef: bbtypes.PullRequestRef{ID: "refs/heads/" + p.Branch, DisplayID: p.Branch, LatestCommit: p.SHA, Repository: repo},
+			ToRef:   bbtypes.PullRequestRef{ID: "refs/heads/" + p.BaseBranch, DisplayID: p.BaseBranch, LatestCommit: p.BeforeSHA, Repository: repo},
+			Author:  &bbtypes.UserWithMetadata{User: actor, Role: "AUTHOR"},
+		}
+		e := &bitbucketPullRequestEvent{PullRequestEvent: bbtypes.PullRequestEvent{Actor: actor, PullRequest: pr}, Date: date}
+		switch kind {
+		case PullRequest:
+			e.EventKey = "pr:" + cmp.Or(p.Action, "opened")
+		case IssueComment:
+			e.EventKey = "pr:comment:" + cmp.Or(p.Action, "added")
+			e.Comment = bbtypes.ActivityComment{ID: 1, Text: p.Comment, Author: bbtypes.User{Name: p.Author, Slug: p.Author, DisplayName: p.Author}}
+		case PullRequestReview:
+			e.EventKey = "pr:reviewer:" + cmp.Or(p.Action, strings.ToLower(p.ReviewState))
+			e.Participant = &bbtypes.UserWithMetadata{User: actor, Role: "REVIEWER", Approved: p.ReviewState == "approved", Status: strings.ToUpper(p.ReviewState)}
+		}
+		return e.EventKey, e, nil
+	}
+	return "", nil, fmt.Errorf("unsupported bitbucket event %q", kind)
+}
+
+// bitbucketPushEvent adds the fields Bitbucket Data Center sends that the
+// Pipelines as Code type does not declare.
+type bitbucketPushEvent struct {
+	bbtypes.PushRequestEvent
+	Date string `json:"date"`
+}
+
+// bitbucketPullRequestEvent adds the date and, for reviewer events, the
+// participant Bitbucket Data Center sends.
+type bitbucketPullRequestEvent struct {
+	bbtypes.PullRequestEvent
+	Date        string                    `json:"date"`
+	Participant *bbtypes.UserWithMetadata `json:"participant,omitempty"`
+}
@@ -0,0 +1,226 @@
+package triggers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega" //nolint:revive,staticcheck // dot import is idiomatic for Gomega
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// field returns the value at a dotted path such as changes[0].ref.displayId,
+// the syntax of $(body...) in TriggerBindings.
+func field(t *testing.T, payload []byte, path string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(payload, &v); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range strings.Split(path, ".") {
+		name, index, indexed := strings.Cut(strings.TrimSuffix(part, "]"), "[")
+		obj, ok := v.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: %s is not an object", path, name)
+		}
+		v = obj[name]
+		if indexed {
+			i, _ := strconv.Atoi(index)
+			list, ok := v.([]any)
+			if !ok || i >= len(list) {
+				t.Fatalf("%s: %s has no element %d", path, name, i)
+			}
+			v = list[i]
+		}
+	}
+	return v
+}
+
+func TestNewEvent(t *testing.T) {
+	const sha = "6b4ba31db35b0ffe20e0eb223602638d155f660a"
+	params := EventParams{
+		Repo:    "tektoncd/triggers",
+		Branch:  "feature",
+		SHA:     sha,
+		Author:  "octocat",
+		Labels:  []string{"ok-to-test"},
+		Number:  42,
+		Comment: "/retest",
+		Tag:     "v0.36.0",
+	}
+	tests := []struct {
+		provider  Provider
+		kind      EventKind
+		eventType string
+		fields    map[string]any
+	}{{
+		provider: GitHub, kind: Push, eventType: "push",
+		fields: map[string]any{
+			"ref":                  "refs/heads/feature",
+			"head_commit.id":       sha,
+			"repository.url":       "https://github.com/tektoncd/triggers",
+			"repository.clone_url": "https://github.com/tektoncd/triggers.git",
+			"sender.login":         "octocat",
+		},
+	}, {
+		provider: GitHub, kind: TagPush, eventType: "push",
+		fields: map[string]any{"ref": "refs/tags/v0.36.0", "after": sha},
+	}, {
+		provider: GitHub, kind: PullRequest, eventType: "pull_request",
+		fields: map[string]any{
+			"action":                      "opened",
+			"number":                      float64(42),
+			"pull_request.head.sha":       sha,
+			"pull_request.head.ref":       "feature",
+			"pull_request.base.ref":       "main",
+			"pull_request.labels[0].name": "ok-to-test",
+			"repository.clone_url":        "https://github.com/tektoncd/triggers.git",
+		},
+	}, {
+		provider: GitHub, kind: IssueComment, eventType: "issue_comment",
+		fields: map[string]any{
+			"comment.body":                "/retest",
+			"comment.user.login":          "octocat",
+			"issue.pull_request.html_url": "https://github.com/tektoncd/triggers/pull/42",
+		},
+	}, {
+		provider: GitHub, kind: PullRequestReview, eventType: "pull_request_review",
+		fields: map[string]any{"action": "submitted", "review.state": "approved", "review.commit_id": sha},
+	}, {
+		provider: GitLab, kind: Push, eventType: "Push Hook",
+		fields: map[string]any{
+			"object_kind":                 "push",
+			"checkout_sha":                sha,
+			"repository.git_http_url":     "https://gitlab.com/tektoncd/triggers.git",
+			"project.path_with_namespace": "tektoncd/triggers",
+		},
+	}, {
+		provider: GitLab, kind: TagPush, eventType: "Tag Push Hook",
+		fields: map[string]any{"object_kind": "tag_push", "ref": "refs/tags/v0.36.0"},
+	}, {
+		provider: GitLab, kind: MergeRequest, eventType: "Merge Request Hook",
+		fields: map[string]any{
+			"object_attributes.action":         "open",
+			"object_attributes.iid":            float64(42),
+			"object_attributes.last_commit.id": sha,
+			"object_attributes.source_branch":  "feature",
+			"labels[0].title":                  "ok-to-test",
+		},
+	}, {
+		provider: GitLab, kind: IssueComment, eventType: "Note Hook",
+		fields: map[string]any{"object_attributes.note": "/retest", "merge_request.iid": float64(42)},
+	}, {
+		provider: GitLab, kind: PullRequestReview, eventType: "Merge Request Hook",
+		fields: map[string]any{"object_attributes.action": "approved"},
+	}, {
+		provider: Bitbucket, kind: Push, eventType: "refs_changed",
+		fields: map[string]any{
+			"eventKey":                       "repo:refs_changed",
+			"changes[0].ref.displayId":       "feature",
+			"changes[0].toHash":              sha,
+			"repository.links.clone[0].href": "http://localhost:7990/scm/tektoncd/triggers.git",
+		},
+	}, {
+		provider: Bitbucket, kind: TagPush, eventType: "refs_changed",
+		fields: map[string]any{"changes[0].ref.type": "TAG", "changes[0].ref.displayId": "v0.36.0"},
+	}, {
+		provider: Bitbucket, kind: PullRequest, eventType: "pr:opened",
+		fields: map[string]any{"pullRequest.id": float64(42), "pullRequest.fromRef.latestCommit": sha},
+	}, {
+		provider: Bitbucket, kind: IssueComment, eventType: "pr:comment:added",
+		fields: map[string]any{"comment.text": "/retest"},
+	}, {
+		provider: Bitbucket, kind: PullRequestReview, eventType: "pr:reviewer:approved",
+		fields: map[string]any{"participant.approved": true},
+	}}
+	for _, tt := range tests {
+		t.Run(string(tt.provider)+"/"+string(tt.kind), func(t *testing.T) {
+			e, err := NewEvent(tt.provider, tt.kind, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Type != tt.eventType {
+				t.Fatalf("Type = %q, want %q", e.Type, tt.eventType)
+			}
+			for path, want := range tt.fields {
+				if got := field(t, e.Payload, path); got != want {
+					t.Fatalf("%s = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewEventDefaultsAndErrors(t *testing.T) {
+	e, err := NewEvent(GitHub, Push, EventParams{Repo: "tektoncd/triggers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Params.SHA) != 40 || e.Params.Branch != "main" || e.Params.Author == "" {
+		t.Fatalf("Params = %+v, want defaults filled in", e.Params)
+	}
+	if got := field(t, e.Payload, "head_commit.id"); got != e.Params.SHA {
+		t.Fatalf("head_commit.id = %v, want the generated %s", got, e.Params.SHA)
+	}
+
+	for name, call := range map[string]func() error{
+		"repo without owner": func() error { _, err := NewEvent(GitHub, Push, EventParams{Repo: "triggers"}); return err },
+		"tag push without tag": func() error {
+			_, err := NewEvent(GitLab, TagPush, EventParams{Repo: "tektoncd/triggers"})
+			return err
+		},
+		"unknown provider": func() error { _, err := NewEvent("gitea", Push, EventParams{Repo: "tektoncd/triggers"}); return err },
+		"unknown kind": func() error {
+			_, err := NewEvent(GitHub, "deployment", EventParams{Repo: "tektoncd/triggers"})
+			return err
+		},
+	} {
+		if call() == nil {
+			t.Errorf("%s: NewEvent() error = nil", name)
+		}
+	}
+}
+
+func TestMockPostGeneratedEvent(t *testing.T) {
+	RegisterTestingT(t)
+	var got http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		provider Provider
+		kind     EventKind
+		header   string
+		want     string
+	}{
+		{provider: GitHub, kind: PullRequest, header: "X-GitHub-Event", want: "pull_request"},
+		{provider: GitLab, kind: TagPush, header: "X-Gitlab-Event", want: "Tag Push Hook"},
+		{provider: Bitbucket, kind: Push, header: "X-Event-Key", want: "repo:refs_changed"},
+		{provider: Bitbucket, kind: PullRequest, header: "X-Event-Key", want: "pr:opened"},
+	} {
+		e := MustNewEvent(tt.provider, tt.kind, EventParams{Repo: "tektoncd/triggers", Tag: "v0.36.0"})
+		resp := MockPostGeneratedEvent(server.URL, e, false)
+		_ = resp.Body.Close()
+		if got.Get(tt.header) != tt.want {
+			t.Fatalf("%s %s: %s = %q, want %q", tt.provider, tt.kind, tt.header, got.Get(tt.header), tt.want)
+		}
+		if string(body) != string(e.Payload) {
+			t.Fatalf("%s %s: sink received a different payload", tt.provider, tt.kind)
+		}
+		if tt.provider == GitHub {
+			if want := "sha256=" + GetSignature(e.Payload, config.TriggersSecretToken); got.Get("X-Hub-Signature-256") != want {
+				t.Fatalf("X-Hub-Signature-256 = %q, want %q", got.Get("X-Hub-Signature-256"), want)
+			}
+		}
+	}
+}
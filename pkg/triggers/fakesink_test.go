@@ -0,0 +1,113 @@
+package triggers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //nolint:revive,staticcheck // dot import is idiomatic for Gomega
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/triggers/triggerstest"
+)
+
+func TestFakeSinkAcceptsFixtures(t *testing.T) {
+	tests := []struct {
+		interceptor string
+		eventType   string
+		payload     string
+	}{
+		{interceptor: "github", eventType: "push", payload: "testdata/push.json"},
+		{interceptor: "GitHub", eventType: "push", payload: "testdata/triggers/github-ctb/push.json"},
+		{interceptor: "github", eventType: "pull_request", payload: "testdata/triggers/github-ctb/pr.json"},
+		{interceptor: "github", eventType: "issue_comment", payload: "testdata/triggers/github-ctb/issue-comment.json"},
+		{interceptor: "github", eventType: "pull_request", payload: "testdata/triggers/triggersCRD/pull-request.json"},
+		{interceptor: "gitlab", eventType: "Push Hook", payload: "testdata/triggers/gitlab/gitlab-push-event.json"},
+		{interceptor: "bitbucket", eventType: "refs_changed", payload: "testdata/triggers/bitbucket/refs-change-event.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			// MockPostEvent and AssertSinkResponse assert through the global Gomega.
+			RegisterTestingT(t)
+			g := NewWithT(t)
+			fake := triggerstest.NewSink(t, triggerstest.SinkConfig{EventListener: "listener", Namespace: "releasetest", Interceptor: tt.interceptor})
+			resp, payload := MockPostEvent(fake.URL, tt.interceptor, tt.eventType, tt.payload, false)
+			g.Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+			got := AssertSinkResponse(resp, "listener", "releasetest")
+
+			events := fake.Events()
+			g.Expect(events).To(HaveLen(1))
+			g.Expect(events[0].ID).To(Equal(got.EventID))
+			g.Expect(string(events[0].Body)).To(Equal(string(payload)))
+			g.Expect(events[0].Accepted).To(BeTrue(), "%s interceptor rejected %s: %s", tt.interceptor, tt.payload, events[0].Reason)
+		})
+	}
+}
+
+func TestFakeSinkAcceptsGeneratedEvents(t *testing.T) {
+	RegisterTestingT(t)
+	tests := []struct {
+		interceptor string
+		provider    Provider
+		kind        EventKind
+	}{
+		{interceptor: "github", provider: GitHub, kind: PullRequestReview},
+		{interceptor: "gitlab", provider: GitLab, kind: MergeRequest},
+		{interceptor: "bitbucket", provider: Bitbucket, kind: TagPush},
+	}
+	for _, tt := range tests {
+		fake := triggerstest.NewSink(t, triggerstest.SinkConfig{Interceptor: tt.interceptor})
+		e := MustNewEvent(tt.provider, tt.kind, EventParams{Repo: "tektoncd/triggers", Tag: "v1"})
+		_ = MockPostGeneratedEvent(fake.URL, e, false).Body.Close()
+		if rejected := fake.Rejected(); len(rejected) != 0 {
+			t.Fatalf("%s %s rejected: %s", tt.provider, tt.kind, rejected[0].Reason)
+		}
+	}
+
+	for _, interceptor := range []string{"gitea", "forgejo", "bitbucket-cloud"} {
+		fake := triggerstest.NewSink(t, triggerstest.SinkConfig{Interceptor: interceptor})
+		e := MustNewEvent(GitHub, Push, EventParams{Repo: "tektoncd/triggers"})
+		_ = postEvent(fake.URL, interceptor, "push", e.Payload, false).Body.Close()
+		if rejected := fake.Rejected(); len(rejected) != 0 {
+			t.Fatalf("%s headers rejected: %s", interceptor, rejected[0].Reason)
+		}
+	}
+}
+
+func TestFakeSinkRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        triggerstest.SinkConfig
+		wantReason string
+	}{
+		{
+			name:       "wrong secret",
+			cfg:        triggerstest.SinkConfig{Interceptor: "github", Secret: "not-the-secret"},
+			wantReason: "signature",
+		},
+		{
+			name:       "event type not allowed",
+			cfg:        triggerstest.SinkConfig{Interceptor: "github", EventTypes: []string{"pull_request"}},
+			wantReason: "event type push is not allowed",
+		},
+		{
+			name:       "gitlab token mismatch",
+			cfg:        triggerstest.SinkConfig{Interceptor: "gitlab", Secret: "not-the-secret"},
+			wantReason: "Invalid X-GitLab-Token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterTestingT(t)
+			g := NewWithT(t)
+			fake := triggerstest.NewSink(t, tt.cfg)
+			eventType := "push"
+			if tt.cfg.Interceptor == "gitlab" {
+				eventType = "Push Hook"
+			}
+			resp, _ := MockPostEvent(fake.URL, tt.cfg.Interceptor, eventType, "testdata/push.json", false)
+			AssertSinkResponse(resp, "fake-listener", "default")
+
+			rejected := fake.Rejected()
+			g.Expect(rejected).To(HaveLen(1))
+			g.Expect(rejected[0].Reason).To(ContainSubstring(tt.wantReason))
+		})
+	}
+}
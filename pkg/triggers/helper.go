@@ -2,11 +2,10 @@
 package triggers
 
 import (
-	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/hex"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -14,7 +13,6 @@ import (
 	"time"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
-	. "github.com/onsi/gomega"    //nolint:revive,staticcheck // dot import is idiomatic for Gomega
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
 )
@@ -66,37 +64,19 @@ func CreateHTTPSClient() *http.Client {
 
 // GetSignature is a HMAC SHA256 generator.
 func GetSignature(input []byte, key string) string {
-	keyForSign := []byte(key)
-	h := hmac.New(sha256.New, keyForSign)
-	_, err := h.Write(input)
-	Expect(err).NotTo(HaveOccurred(), "could not generate signature")
-	return hex.EncodeToString(h.Sum(nil))
+	return sign(sha256.New, key, input)
 }
 
-// BuildHeaders adds interceptor-specific headers to the HTTP request.
-// The payload parameter is used for HMAC signature calculation.
+// BuildHeaders adds the headers of the interceptor registered as interceptor, such
+// as github, gitlab, bitbucket, bitbucket-cloud, gitea, forgejo, slack, hmac or
+// bearer, to the HTTP request. The payload parameter is used for HMAC signature
+// calculation. See RegisterInterceptor for adding interceptors.
 func BuildHeaders(req *http.Request, interceptor, eventType string, payload []byte) *http.Request {
-	switch strings.ToLower(interceptor) {
-	case "github":
-		log.Printf("Building headers for github interceptor..")
-		req.Header.Add("Accept", "application/json")
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("X-Hub-Signature-256", "sha256="+GetSignature(payload, config.TriggersSecretToken))
-		req.Header.Add("X-GitHub-Event", eventType)
-	case "gitlab":
-		log.Printf("Building headers for gitlab interceptor..")
-		req.Header.Add("Accept", "application/json")
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("X-GitLab-Token", config.TriggersSecretToken)
-		req.Header.Add("X-Gitlab-Event", eventType)
-	case "bitbucket":
-		log.Printf("Building headers for bitbucket interceptor..")
-		req.Header.Add("Accept", "application/json")
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("X-Hub-Signature", "sha256="+GetSignature(payload, config.TriggersSecretToken))
-		req.Header.Add("X-Event-Key", "repo:"+eventType)
-	default:
-		Fail("unsupported interceptor type: " + interceptor)
+	strategy, ok := LookupInterceptor(interceptor)
+	if !ok {
+		Fail(fmt.Sprintf("unsupported interceptor type: %s, want one of %s", interceptor, strings.Join(Interceptors(), ", ")))
 	}
+	log.Printf("Building headers for %s interceptor..", strings.ToLower(interceptor))
+	strategy(req, eventType, payload)
 	return req
 }
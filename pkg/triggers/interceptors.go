@@ -0,0 +1,214 @@
+package triggers
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // G505: interceptors still verify HMAC-SHA1 signatures
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// HeaderStrategy adds the headers and signature an interceptor verifies to a
+// request carrying payload. eventType is passed through from MockPostEvent.
+type HeaderStrategy func(req *http.Request, eventType string, payload []byte)
+
+var interceptors = struct {
+	mu         sync.RWMutex
+	strategies map[string]HeaderStrategy
+}{
+	strategies: map[string]HeaderStrategy{},
+}
+
+func init() {
+	RegisterInterceptor("github", githubHeaders)
+	RegisterInterceptor("gitlab", gitlabHeaders)
+	RegisterInterceptor("bitbucket", bitbucketServerHeaders)
+	RegisterInterceptor("bitbucket-cloud", bitbucketCloudHeaders)
+	RegisterInterceptor("gitea", giteaHeaders("Gitea"))
+	RegisterInterceptor("forgejo", giteaHeaders("Forgejo"))
+	RegisterInterceptor("slack", slackHeaders)
+	RegisterInterceptor("bearer", BearerHeaders(""))
+	RegisterInterceptor("hmac", MustHMACHeaders(HMACConfig{}))
+}
+
+// RegisterInterceptor makes strategy available to BuildHeaders and MockPostEvent
+// under name, case-insensitively. Registering a name again replaces its strategy,
+// so a suite can register the HMAC or bearer settings of its own ClusterInterceptor.
+func RegisterInterceptor(name string, strategy HeaderStrategy) {
+	interceptors.mu.Lock()
+	defer interceptors.mu.Unlock()
+	interceptors.strategies[strings.ToLower(name)] = strategy
+}
+
+// LookupInterceptor returns the strategy registered under name.
+func LookupInterceptor(name string) (HeaderStrategy, bool) {
+	interceptors.mu.RLock()
+	defer interceptors.mu.RUnlock()
+	strategy, ok := interceptors.strategies[strings.ToLower(name)]
+	return strategy, ok
+}
+
+// Interceptors returns the registered interceptor names in sorted order.
+func Interceptors() []string {
+	interceptors.mu.RLock()
+	defer interceptors.mu.RUnlock()
+	names := make([]string, 0, len(interceptors.strategies))
+	for name := range interceptors.strategies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// hashes are the algorithms HMACConfig accepts.
+var hashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACConfig configures a generic HMAC signature for custom interceptors and
+// ClusterInterceptors.
+type HMACConfig struct {
+	// Header carries the signature. Defaults to X-Signature.
+	Header string
+	// Algorithm is sha1, sha256 or sha512. Defaults to sha256.
+	Algorithm string
+	// Prefix is prepended to the hex digest, e.g. "sha256=". Defaults to none.
+	Prefix string
+	// Secret is the HMAC key. Defaults to config.TriggersSecretToken, read when
+	// each request is signed.
+	Secret string
+	// EventHeader, when set, carries the eventType.
+	EventHeader string
+}
+
+// HMACHeaders returns a strategy signing the payload as cfg describes.
+func HMACHeaders(cfg HMACConfig) (HeaderStrategy, error) {
+	cfg.Algorithm = strings.ToLower(cmp.Or(cfg.Algorithm, "sha256"))
+	newHash, ok := hashes[cfg.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported HMAC algorithm %q, want sha1, sha256 or sha512", cfg.Algorithm)
+	}
+	cfg.Header = cmp.Or(cfg.Header, "X-Signature")
+	return func(req *http.Request, eventType string, payload []byte) {
+		jsonHeaders(req)
+		req.Header.Set(cfg.Header, cfg.Prefix+sign(newHash, cmp.Or(cfg.Secret, config.TriggersSecretToken), payload))
+		if cfg.EventHeader != "" {
+			req.Header.Set(cfg.EventHeader, eventType)
+		}
+	}, nil
+}
+
+// MustHMACHeaders is HMACHeaders for package-level registrations; it panics on
+// an unsupported algorithm.
+func MustHMACHeaders(cfg HMACConfig) HeaderStrategy {
+	strategy, err := HMACHeaders(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return strategy
+}
+
+// BearerHeaders returns a strategy sending token as an Authorization bearer
+// token. An empty token sends config.TriggersSecretToken, read when each
+// request is built.
+func BearerHeaders(token string) HeaderStrategy {
+	return func(req *http.Request, _ string, _ []byte) {
+		jsonHeaders(req)
+		req.Header.Set("Authorization", "Bearer "+cmp.Or(token, config.TriggersSecretToken))
+	}
+}
+
+func jsonHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+}
+
+// sign returns the hex encoded HMAC of payload.
+func sign(newHash func() hash.Hash, secret string, payload []byte) string {
+	h := hmac.New(newHash, []byte(secret))
+	_, _ = h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func githubHeaders(req *http.Request, eventType string, payload []byte) {
+	jsonHeaders(req)
+	req.Header.Add("X-Hub-Signature-256", "sha256="+sign(sha256.New, config.TriggersSecretToken, payload))
+	req.Header.Add("X-GitHub-Event", eventType)
+}
+
+func gitlabHeaders(req *http.Request, eventType string, _ []byte) {
+	jsonHeaders(req)
+	req.Header.Add("X-GitLab-Token", config.TriggersSecretToken)
+	req.Header.Add("X-Gitlab-Event", eventType)
+}
+
+// bitbucketEventKey namespaces a bare eventType such as refs_changed as a repo:
+// event; keys like pr:opened are sent as they are.
+func bitbucketEventKey(eventType string) string {
+	if !strings.Contains(eventType, ":") {
+		return "repo:" + eventType
+	}
+	return eventType
+}
+
+func bitbucketServerHeaders(req *http.Request, eventType string, payload []byte) {
+	jsonHeaders(req)
+	req.Header.Add("X-Hub-Signature", "sha256="+sign(sha256.New, config.TriggersSecretToken, payload))
+	req.Header.Add("X-Event-Key", bitbucketEventKey(eventType))
+}
+
+// bitbucketCloudHeaders sends what bitbucket.org sends for a webhook with a
+// secret, e.g. repo:push or pullrequest:created.
+func bitbucketCloudHeaders(req *http.Request, eventType string, payload []byte) {
+	jsonHeaders(req)
+	req.Header.Set("User-Agent", "Bitbucket-Webhooks/2.0")
+	req.Header.Add("X-Hub-Signature", "sha256="+sign(sha256.New, config.TriggersSecretToken, payload))
+	req.Header.Add("X-Event-Key", bitbucketEventKey(eventType))
+	req.Header.Add("X-Hook-UUID", uuid.NewString())
+	req.Header.Add("X-Request-UUID", uuid.NewString())
+	req.Header.Add("X-Attempt-Number", "1")
+}
+
+// giteaHeaders returns the headers of Gitea or of Forgejo, which also sends the
+// Gitea ones. Both add GitHub compatible headers, so the github interceptor
+// accepts their events too.
+func giteaHeaders(product string) HeaderStrategy {
+	return func(req *http.Request, eventType string, payload []byte) {
+		jsonHeaders(req)
+		signature := sign(sha256.New, config.TriggersSecretToken, payload)
+		delivery := uuid.NewString()
+		for _, p := range slices.Compact([]string{product, "Gitea"}) {
+			req.Header.Add("X-"+p+"-Event", eventType)
+			req.Header.Add("X-"+p+"-Event-Type", eventType)
+			req.Header.Add("X-"+p+"-Signature", signature)
+			req.Header.Add("X-"+p+"-Delivery", delivery)
+		}
+		req.Header.Add("X-Gogs-Event", eventType)
+		req.Header.Add("X-GitHub-Event", eventType)
+		req.Header.Add("X-Hub-Signature-256", "sha256="+signature)
+	}
+}
+
+// slackHeaders signs "v0:<timestamp>:<payload>" the way Slack signs requests.
+func slackHeaders(req *http.Request, _ string, payload []byte) {
+	jsonHeaders(req)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	base := append([]byte("v0:"+timestamp+":"), payload...)
+	req.Header.Add("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Add("X-Slack-Signature", "v0="+sign(sha256.New, config.TriggersSecretToken, base))
+}
@@ -0,0 +1,127 @@
+package triggers
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // G505: verifying HMAC-SHA1 signatures
+	"crypto/sha256"
+	"crypto/sha512"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+func newRequest(t *testing.T, payload []byte) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://el.example.com", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestBuildHeaders(t *testing.T) {
+	payload := []byte(`{"ref":"refs/heads/main"}`)
+	signature := sign(sha256.New, config.TriggersSecretToken, payload)
+	tests := []struct {
+		interceptor string
+		eventType   string
+		want        map[string]string
+	}{{
+		interceptor: "GitHub", eventType: "push",
+		want: map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + signature},
+	}, {
+		interceptor: "gitlab", eventType: "Push Hook",
+		want: map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": config.TriggersSecretToken},
+	}, {
+		interceptor: "bitbucket", eventType: "refs_changed",
+		want: map[string]string{"X-Event-Key": "repo:refs_changed", "X-Hub-Signature": "sha256=" + signature},
+	}, {
+		interceptor: "bitbucket-cloud", eventType: "pullrequest:created",
+		want: map[string]string{
+			"X-Event-Key":      "pullrequest:created",
+			"X-Hub-Signature":  "sha256=" + signature,
+			"User-Agent":       "Bitbucket-Webhooks/2.0",
+			"X-Attempt-Number": "1",
+		},
+	}, {
+		interceptor: "gitea", eventType: "push",
+		want: map[string]string{"X-Gitea-Event": "push", "X-Gitea-Signature": signature, "X-Hub-Signature-256": "sha256=" + signature},
+	}, {
+		interceptor: "forgejo", eventType: "pull_request",
+		want: map[string]string{"X-Forgejo-Event": "pull_request", "X-Forgejo-Signature": signature, "X-Gitea-Event": "pull_request"},
+	}, {
+		interceptor: "hmac", eventType: "push",
+		want: map[string]string{"X-Signature": signature},
+	}, {
+		interceptor: "bearer", eventType: "push",
+		want: map[string]string{"Authorization": "Bearer " + config.TriggersSecretToken},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.interceptor, func(t *testing.T) {
+			req := BuildHeaders(newRequest(t, payload), tt.interceptor, tt.eventType, payload)
+			if got := req.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			for header, want := range tt.want {
+				if got := req.Header.Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+
+	req := BuildHeaders(newRequest(t, payload), "bitbucket-cloud", "repo:push", payload)
+	if req.Header.Get("X-Hook-UUID") == "" || req.Header.Get("X-Request-UUID") == "" {
+		t.Errorf("bitbucket-cloud headers = %v, want hook and request UUIDs", req.Header)
+	}
+}
+
+func TestSlackHeaders(t *testing.T) {
+	payload := []byte(`{"type":"event_callback"}`)
+	req := BuildHeaders(newRequest(t, payload), "slack", "", payload)
+	timestamp := req.Header.Get("X-Slack-Request-Timestamp")
+	want := "v0=" + sign(sha256.New, config.TriggersSecretToken, []byte("v0:"+timestamp+":"+string(payload)))
+	if timestamp == "" || req.Header.Get("X-Slack-Signature") != want {
+		t.Fatalf("slack headers = %v, want X-Slack-Signature %s", req.Header, want)
+	}
+}
+
+func TestHMACHeaders(t *testing.T) {
+	payload := []byte(`{}`)
+	tests := []struct {
+		cfg  HMACConfig
+		want string
+	}{
+		{cfg: HMACConfig{Header: "X-Hub-Signature", Algorithm: "sha1", Prefix: "sha1="}, want: "sha1=" + sign(sha1.New, config.TriggersSecretToken, payload)},
+		{cfg: HMACConfig{Header: "X-Hub-Signature", Algorithm: "SHA512", Secret: "s3cr3t"}, want: sign(sha512.New, "s3cr3t", payload)},
+	}
+	for _, tt := range tests {
+		strategy, err := HMACHeaders(tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := newRequest(t, payload)
+		strategy(req, "push", payload)
+		if got := req.Header.Get(tt.cfg.Header); got != tt.want {
+			t.Fatalf("HMACHeaders(%+v) signed %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+
+	if _, err := HMACHeaders(HMACConfig{Algorithm: "md5"}); err == nil {
+		t.Fatal("HMACHeaders() accepted md5")
+	}
+}
+
+func TestRegisterInterceptor(t *testing.T) {
+	RegisterInterceptor("Release-Tests-Custom", MustHMACHeaders(HMACConfig{Header: "X-Custom-Signature", EventHeader: "X-Custom-Event"}))
+	if !slices.Contains(Interceptors(), "release-tests-custom") {
+		t.Fatalf("Interceptors() = %v, want the custom interceptor", Interceptors())
+	}
+	payload := []byte(`{}`)
+	req := BuildHeaders(newRequest(t, payload), "release-tests-custom", "build", payload)
+	if req.Header.Get("X-Custom-Event") != "build" || req.Header.Get("X-Custom-Signature") == "" {
+		t.Fatalf("custom interceptor headers = %v", req.Header)
+	}
+}
@@ -0,0 +1,345 @@
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/triggers/pkg/apis/triggers"
+	"github.com/tektoncd/triggers/pkg/sink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// LoadConfig configures RunLoad.
+type LoadConfig struct {
+	// RouteURL is the EventListener route, as returned by ExposeEventListener.
+	RouteURL string
+	// TLS sends the events over https with CreateHTTPSClient, like MockPostEvent.
+	TLS bool
+	// Events is the number of events to send.
+	Events int
+	// Rate is the number of events started per second. Zero sends them as fast
+	// as Concurrency allows.
+	Rate float64
+	// Concurrency is the number of requests in flight at most. Defaults to 1.
+	Concurrency int
+	// Event builds the i-th event, from 0 to Events-1. Events built by NewEvent
+	// get a random SHA each, so every PipelineRun can be told apart by its params.
+	Event func(i int) (*Event, error)
+	// Interceptor names the header strategy, see BuildHeaders. Defaults to the
+	// provider of each event.
+	Interceptor string
+	// Client overrides the HTTP client RunLoad picks from TLS.
+	Client *http.Client
+}
+
+// SentEvent is the outcome of one event of a load run.
+type SentEvent struct {
+	Event *Event
+	// StatusCode is zero when the request failed and Err is set.
+	StatusCode int
+	Latency    time.Duration
+	// EventID is the event ID of the sink response, which Triggers puts on the
+	// resources it creates as the triggers.tekton.dev/triggers-eventid label.
+	EventID string
+	Err     error
+}
+
+// LoadResult is the outcome of RunLoad.
+type LoadResult struct {
+	Sent     []SentEvent
+	Duration time.Duration
+}
+
+// RunLoad fires cfg.Events events at the EventListener route, paced at cfg.Rate
+// with at most cfg.Concurrency in flight. Unlike MockPostEvent it does not fail
+// on an unexpected response; it records every response code and latency so a
+// spec can assert on them, and then reconcile the PipelineRuns with
+// ReconcilePipelineRuns. It returns an error only for an invalid cfg or an
+// event it could not build, before anything is sent. Once ctx is done no more
+// events are sent, and the events left record ctx.Err() as their Err.
+func RunLoad(ctx context.Context, cfg LoadConfig) (*LoadResult, error) {
+	switch {
+	case cfg.Events <= 0:
+		return nil, fmt.Errorf("load run needs at least one event, got %d", cfg.Events)
+	case cfg.Event == nil:
+		return nil, fmt.Errorf("load run needs an Event builder")
+	case cfg.Rate < 0:
+		return nil, fmt.Errorf("load run rate must not be negative, got %g", cfg.Rate)
+	}
+	cfg.Concurrency = max(cfg.Concurrency, 1)
+	if cfg.Interceptor != "" {
+		if _, ok := LookupInterceptor(cfg.Interceptor); !ok {
+			return nil, fmt.Errorf("unsupported interceptor type: %s, want one of %s", cfg.Interceptor, strings.Join(Interceptors(), ", "))
+		}
+	}
+	url := cfg.RouteURL
+	client := cfg.Client
+	if cfg.TLS {
+		url = "https://" + strings.Split(url, "//")[1]
+		if client == nil {
+			client = CreateHTTPSClient()
+		}
+	}
+	if client == nil {
+		client = CreateHTTPClient()
+	}
+
+	// Build the events up front so a broken builder fails before anything is sent.
+	result := &LoadResult{Sent: make([]SentEvent, cfg.Events)}
+	for i := range result.Sent {
+		event, err := cfg.Event(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build event %d: %w", i, err)
+		}
+		result.Sent[i].Event = event
+	}
+
+	log.Printf("Sending %d events to %s at %s with concurrency %d", cfg.Events, url, rateString(cfg.Rate), cfg.Concurrency)
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range cfg.Concurrency {
+		wg.Go(func() {
+			for i := range queue {
+				result.Sent[i] = send(ctx, client, url, cfg.Interceptor, result.Sent[i].Event)
+			}
+		})
+	}
+
+	start := time.Now()
+	var ticker *time.Ticker
+	if cfg.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+		defer ticker.Stop()
+	}
+	for i := range cfg.Events {
+		if ticker != nil && i > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() == nil {
+			select {
+			case queue <- i:
+				continue
+			case <-ctx.Done():
+			}
+		}
+		for j := i; j < cfg.Events; j++ {
+			result.Sent[j].Err = ctx.Err()
+		}
+		break
+	}
+	close(queue)
+	wg.Wait()
+	result.Duration = time.Since(start)
+	log.Printf("Load run finished: %s", result)
+	return result, nil
+}
+
+func rateString(rate float64) string {
+	if rate == 0 {
+		return "full speed"
+	}
+	return fmt.Sprintf("%g events/s", rate)
+}
+
+// send posts one event and records its outcome.
+func send(ctx context.Context, client *http.Client, url, interceptor string, event *Event) SentEvent {
+	sent := SentEvent{Event: event}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(event.Payload))
+	if err != nil {
+		sent.Err = err
+		return sent
+	}
+	if interceptor == "" {
+		interceptor = string(event.Provider)
+	}
+	strategy, ok := LookupInterceptor(interceptor)
+	if !ok {
+		sent.Err = fmt.Errorf("unsupported interceptor type: %s", interceptor)
+		return sent
+	}
+	strategy(req, event.Type, event.Payload)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	sent.Latency = time.Since(start)
+	if err != nil {
+		sent.Err = err
+		return sent
+	}
+	//nolint:errcheck
+	defer resp.Body.Close()
+	sent.StatusCode = resp.StatusCode
+	var body sink.Response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil {
+		sent.EventID = body.EventID
+	}
+	return sent
+}
+
+// Codes counts the responses by status code; failed requests count as 0.
+func (r *LoadResult) Codes() map[int]int {
+	codes := map[int]int{}
+	for _, sent := range r.Sent {
+		codes[sent.StatusCode]++
+	}
+	return codes
+}
+
+// Accepted returns the events the sink answered with 202 Accepted and an event ID.
+func (r *LoadResult) Accepted() []SentEvent {
+	var accepted []SentEvent
+	for _, sent := range r.Sent {
+		if sent.StatusCode == http.StatusAccepted && sent.EventID != "" {
+			accepted = append(accepted, sent)
+		}
+	}
+	return accepted
+}
+
+// Percentile returns the p-th percentile (0 to 100) of the latencies of the
+// requests that got a response, by the nearest-rank method.
+func (r *LoadResult) Percentile(p float64) time.Duration {
+	var latencies []time.Duration
+	for _, sent := range r.Sent {
+		if sent.Err == nil {
+			latencies = append(latencies, sent.Latency)
+		}
+	}
+	if len(latencies) == 0 {
+		return 0
+	}
+	slices.Sort(latencies)
+	rank := int(math.Ceil(p/100*float64(len(latencies)))) - 1
+	return latencies[min(max(rank, 0), len(latencies)-1)]
+}
+
+// String summarizes the run, e.g. for a spec's failure message.
+func (r *LoadResult) String() string {
+	codes := r.Codes()
+	var parts []string
+	for _, code := range slices.Sorted(maps.Keys(codes)) {
+		name := fmt.Sprint(code)
+		if code == 0 {
+			name = "error"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", name, codes[code]))
+	}
+	return fmt.Sprintf("%d events in %s, codes %s, latency p50=%s p90=%s p99=%s max=%s",
+		len(r.Sent), r.Duration.Round(time.Millisecond), strings.Join(parts, " "),
+		r.Percentile(50), r.Percentile(90), r.Percentile(99), r.Percentile(100))
+}
+
+// ReconcileSettle is how long ReconcilePipelineRuns keeps watching after every
+// event has a PipelineRun, so a duplicate created a moment later is counted.
+var ReconcileSettle = 15 * time.Second
+
+// ReconcilePipelineRuns waits until the EventListener elname has created a
+// PipelineRun in namespace for every accepted event of the load run, and checks
+// that it created exactly one per event and that each carries the params want
+// returns for its event, e.g. {"gitrevision": e.Params.SHA}. PipelineRuns are
+// matched to events by their triggers.tekton.dev/triggers-eventid label. Once
+// every event has a PipelineRun it lists them again after ReconcileSettle and
+// compares that list.
+func ReconcilePipelineRuns(c *clients.Clients, result *LoadResult, elname, namespace string, want func(e *Event) map[string]string) error {
+	accepted := result.Accepted()
+	if len(accepted) != len(result.Sent) {
+		return fmt.Errorf("only %d of %d events were accepted: %s", len(accepted), len(result.Sent), result)
+	}
+	selector := labels.SelectorFromSet(labels.Set{triggers.GroupName + triggers.EventListenerLabelKey: elname}).String()
+	listByEvent := func(ctx context.Context) (map[string][]pipelinev1.PipelineRun, error) {
+		list, err := c.Tekton.TektonV1().PipelineRuns(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		byEvent := map[string][]pipelinev1.PipelineRun{}
+		for _, pr := range list.Items {
+			id := pr.Labels[triggers.GroupName+triggers.EventIDLabelKey]
+			byEvent[id] = append(byEvent[id], pr)
+		}
+		return byEvent, nil
+	}
+	byEvent := map[string][]pipelinev1.PipelineRun{}
+	err := wait.PollUntilContextTimeout(c.Ctx, config.APIRetry, config.APITimeout, true, func(ctx context.Context) (bool, error) {
+		listed, err := listByEvent(ctx)
+		if err != nil {
+			return false, err
+		}
+		byEvent = listed
+		for _, sent := range accepted {
+			if len(byEvent[sent.EventID]) == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err == nil {
+		select {
+		case <-time.After(ReconcileSettle):
+		case <-c.Ctx.Done():
+		}
+		if byEvent, err = listByEvent(c.Ctx); err != nil {
+			return fmt.Errorf("failed to list PipelineRuns of EventListener %s in namespace %s: %w", elname, namespace, err)
+		}
+	}
+	var problems []string
+	missing := 0
+	for _, sent := range accepted {
+		prs := byEvent[sent.EventID]
+		delete(byEvent, sent.EventID)
+		switch {
+		case len(prs) == 0:
+			missing++
+			continue
+		case len(prs) > 1:
+			problems = append(problems, fmt.Sprintf("event %s created %d PipelineRuns", sent.EventID, len(prs)))
+		}
+		for _, pr := range prs {
+			for name, value := range want(sent.Event) {
+				if got := paramValue(pr, name); got != value {
+					problems = append(problems, fmt.Sprintf("PipelineRun %s of event %s has param %s=%q, want %q", pr.Name, sent.EventID, name, got, value))
+				}
+			}
+		}
+	}
+	if missing > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d events created no PipelineRun: %v", missing, len(accepted), err))
+	}
+	for id, prs := range byEvent {
+		problems = append(problems, fmt.Sprintf("%d PipelineRuns of EventListener %s belong to event %q, which the load run did not send", len(prs), elname, id))
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("PipelineRuns of EventListener %s in namespace %s do not match the load run:\n%s", elname, namespace, strings.Join(problems, "\n"))
+	}
+	log.Printf("EventListener %s created one PipelineRun per event for %d events", elname, len(accepted))
+	return nil
+}
+
+// paramValue returns the string value of the PipelineRun param called name.
+func paramValue(pr pipelinev1.PipelineRun, name string) string {
+	for _, p := range pr.Spec.Params {
+		if p.Name == name {
+			return p.Value.StringVal
+		}
+	}
+	return ""
+}
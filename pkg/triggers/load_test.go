@@ -0,0 +1,252 @@
+package triggers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/triggers/triggerstest"
+)
+
+func pushEvents(int) (*Event, error) {
+	return NewEvent(GitHub, Push, EventParams{Repo: "tektoncd/triggers"})
+}
+
+// runLoad runs RunLoad and fails t on an error.
+func runLoad(t *testing.T, cfg LoadConfig) *LoadResult {
+	t.Helper()
+	result, err := RunLoad(t.Context(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestRunLoad(t *testing.T) {
+	fake := triggerstest.NewSink(t, triggerstest.SinkConfig{Interceptor: "github"})
+	result := runLoad(t, LoadConfig{RouteURL: fake.URL, Events: 20, Concurrency: 4, Event: pushEvents})
+
+	if codes := result.Codes(); len(codes) != 1 || codes[http.StatusAccepted] != 20 {
+		t.Fatalf("Codes() = %v, want 20 accepted", codes)
+	}
+	if len(result.Accepted()) != 20 || len(fake.Events()) != 20 || len(fake.Rejected()) != 0 {
+		t.Fatalf("sink received %d events, rejected %d; %d accepted", len(fake.Events()), len(fake.Rejected()), len(result.Accepted()))
+	}
+	ids := map[string]bool{}
+	for _, event := range fake.Events() {
+		ids[event.ID] = true
+	}
+	for _, sent := range result.Sent {
+		if !ids[sent.EventID] {
+			t.Fatalf("event ID %q of the load run was not issued by the sink", sent.EventID)
+		}
+	}
+	if result.Percentile(50) <= 0 || result.Percentile(50) > result.Percentile(100) {
+		t.Fatalf("latency p50=%s max=%s", result.Percentile(50), result.Percentile(100))
+	}
+}
+
+func TestRunLoadRate(t *testing.T) {
+	fake := triggerstest.NewSink(t, triggerstest.SinkConfig{})
+	result := runLoad(t, LoadConfig{RouteURL: fake.URL, Events: 5, Rate: 50, Concurrency: 5, Event: pushEvents})
+	if result.Duration < 80*time.Millisecond {
+		t.Fatalf("5 events at 50/s took %s, want at least 80ms", result.Duration)
+	}
+}
+
+func TestRunLoadRecordsFailures(t *testing.T) {
+	fake := triggerstest.NewSink(t, triggerstest.SinkConfig{})
+	result := runLoad(t, LoadConfig{RouteURL: fake.URL + "/missing\x7f", Events: 2, Event: pushEvents})
+	if codes := result.Codes(); codes[0] != 2 || result.Sent[0].Err == nil || len(result.Accepted()) != 0 {
+		t.Fatalf("Codes() = %v, Sent[0].Err = %v, want 2 failed requests", codes, result.Sent[0].Err)
+	}
+	if !strings.Contains(result.String(), "error=2") {
+		t.Fatalf("String() = %q, want the failures counted", result.String())
+	}
+}
+
+func TestRunLoadStopsWhenContextIsDone(t *testing.T) {
+	fake := triggerstest.NewSink(t, triggerstest.SinkConfig{})
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	result, err := RunLoad(ctx, LoadConfig{RouteURL: fake.URL, Events: 100, Rate: 20, Event: pushEvents})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := len(fake.Events()); sent == 0 || sent >= 100 {
+		t.Fatalf("sink got %d events, want some but not all of 100", sent)
+	}
+	if last := result.Sent[99]; last.StatusCode != 0 || !errors.Is(last.Err, context.DeadlineExceeded) {
+		t.Fatalf("last event = %+v, want it unsent with %v", last, context.DeadlineExceeded)
+	}
+	if result.Duration > time.Second {
+		t.Fatalf("load run took %s after its context was done", result.Duration)
+	}
+}
+
+func TestRunLoadInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     LoadConfig
+		wantErr string
+	}{{
+		name:    "no events",
+		cfg:     LoadConfig{Event: pushEvents},
+		wantErr: "at least one event",
+	}, {
+		name:    "no builder",
+		cfg:     LoadConfig{Events: 1},
+		wantErr: "needs an Event builder",
+	}, {
+		name:    "negative rate",
+		cfg:     LoadConfig{Events: 1, Rate: -1, Event: pushEvents},
+		wantErr: "must not be negative",
+	}, {
+		name:    "unknown interceptor",
+		cfg:     LoadConfig{Events: 1, Event: pushEvents, Interceptor: "jenkins"},
+		wantErr: "unsupported interceptor type: jenkins",
+	}, {
+		name:    "builder error",
+		cfg:     LoadConfig{Events: 1, Event: func(int) (*Event, error) { return nil, errors.New("boom") }},
+		wantErr: "failed to build event 0: boom",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RunLoad(t.Context(), tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RunLoad() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadResultPercentile(t *testing.T) {
+	result := &LoadResult{}
+	for i := 1; i <= 10; i++ {
+		result.Sent = append(result.Sent, SentEvent{StatusCode: http.StatusAccepted, Latency: time.Duration(i) * time.Millisecond})
+	}
+	result.Sent = append(result.Sent, SentEvent{Latency: time.Hour, Err: context.DeadlineExceeded})
+	for p, want := range map[float64]time.Duration{0: time.Millisecond, 50: 5 * time.Millisecond, 90: 9 * time.Millisecond, 99: 10 * time.Millisecond, 100: 10 * time.Millisecond} {
+		if got := result.Percentile(p); got != want {
+			t.Errorf("Percentile(%g) = %s, want %s", p, got, want)
+		}
+	}
+}
+
+// pipelineRun is the PipelineRun the EventListener creates for sent.
+func pipelineRun(name string, sent SentEvent, revision string) runtime.Object {
+	return &pipelinev1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "releasetest",
+			Labels: map[string]string{
+				"triggers.tekton.dev/eventlistener":    "listener",
+				"triggers.tekton.dev/triggers-eventid": sent.EventID,
+			},
+		},
+		Spec: pipelinev1.PipelineRunSpec{
+			Params: pipelinev1.Params{{Name: "gitrevision", Value: *pipelinev1.NewStructuredValues(revision)}},
+		},
+	}
+}
+
+func TestReconcilePipelineRuns(t *testing.T) {
+	settle := ReconcileSettle
+	ReconcileSettle = 10 * time.Millisecond
+	t.Cleanup(func() { ReconcileSettle = settle })
+	fake := triggerstest.NewSink(t, triggerstest.SinkConfig{Interceptor: "github"})
+	result := runLoad(t, LoadConfig{RouteURL: fake.URL, Events: 3, Concurrency: 3, Event: pushEvents})
+	revision := func(e *Event) map[string]string { return map[string]string{"gitrevision": e.Params.SHA} }
+	sent := result.Sent
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		// late are created once every event has a PipelineRun.
+		late    []runtime.Object
+		wantErr string
+	}{{
+		name: "one PipelineRun per event",
+		objects: []runtime.Object{
+			pipelineRun("run-0", sent[0], sent[0].Event.Params.SHA),
+			pipelineRun("run-1", sent[1], sent[1].Event.Params.SHA),
+			pipelineRun("run-2", sent[2], sent[2].Event.Params.SHA),
+		},
+	}, {
+		name: "missing PipelineRun",
+		objects: []runtime.Object{
+			pipelineRun("run-0", sent[0], sent[0].Event.Params.SHA),
+			pipelineRun("run-1", sent[1], sent[1].Event.Params.SHA),
+		},
+		wantErr: "1 of 3 events created no PipelineRun",
+	}, {
+		name: "duplicate PipelineRun with a wrong param",
+		objects: []runtime.Object{
+			pipelineRun("run-0", sent[0], sent[0].Event.Params.SHA),
+			pipelineRun("run-0-again", sent[0], "main"),
+			pipelineRun("run-1", sent[1], sent[1].Event.Params.SHA),
+			pipelineRun("run-2", sent[2], sent[2].Event.Params.SHA),
+		},
+		wantErr: "created 2 PipelineRuns",
+	}, {
+		name: "duplicate PipelineRun created late",
+		objects: []runtime.Object{
+			pipelineRun("run-0", sent[0], sent[0].Event.Params.SHA),
+			pipelineRun("run-1", sent[1], sent[1].Event.Params.SHA),
+			pipelineRun("run-2", sent[2], sent[2].Event.Params.SHA),
+		},
+		late:    []runtime.Object{pipelineRun("run-2-again", sent[2], sent[2].Event.Params.SHA)},
+		wantErr: "event " + sent[2].EventID + " created 2 PipelineRuns",
+	}, {
+		name: "PipelineRun of another event",
+		objects: []runtime.Object{
+			pipelineRun("run-0", sent[0], sent[0].Event.Params.SHA),
+			pipelineRun("run-1", sent[1], sent[1].Event.Params.SHA),
+			pipelineRun("run-2", sent[2], sent[2].Event.Params.SHA),
+			pipelineRun("stale", SentEvent{EventID: "stale-event"}, "main"),
+		},
+		wantErr: `belong to event "stale-event"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := clients.NewFakeClients(tt.objects...)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			c.Ctx = ctx
+			lists := 0
+			c.PrependReactor("list", "pipelineruns", func(k8stesting.Action) (bool, runtime.Object, error) {
+				if lists++; lists == 2 {
+					for _, obj := range tt.late {
+						if err := c.TektonFake.Tracker().Add(obj); err != nil {
+							t.Error(err)
+						}
+					}
+				}
+				return false, nil, nil
+			})
+
+			err := ReconcilePipelineRuns(c.Clients, result, "listener", "releasetest", revision)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatal(err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("ReconcilePipelineRuns() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := ReconcilePipelineRuns(clients.NewFakeClients().Clients, &LoadResult{Sent: []SentEvent{{StatusCode: http.StatusServiceUnavailable}}}, "listener", "releasetest", revision)
+	if err == nil || !strings.Contains(err.Error(), "only 0 of 1 events were accepted") {
+		t.Fatalf("ReconcilePipelineRuns() error = %v, want the rejected event reported", err)
+	}
+}
@@ -0,0 +1,399 @@
+package triggers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tektoncd/triggers/pkg/apis/triggers"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1beta1"
+	triggersinterceptors "github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/pkg/interceptors/bitbucket"
+	"github.com/tektoncd/triggers/pkg/interceptors/cel"
+	githubinterceptor "github.com/tektoncd/triggers/pkg/interceptors/github"
+	"github.com/tektoncd/triggers/pkg/interceptors/gitlab"
+	"github.com/tektoncd/triggers/pkg/interceptors/slack"
+	"github.com/tektoncd/triggers/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// clusterInterceptors are the ClusterInterceptors Triggers ships, by the name an
+// interceptor ref uses. Other interceptors cannot be previewed.
+var clusterInterceptors = map[string]func(sg staticSecret) triggersv1.InterceptorInterface{
+	"cel":       func(sg staticSecret) triggersv1.InterceptorInterface { return cel.NewInterceptor(sg) },
+	"github":    func(sg staticSecret) triggersv1.InterceptorInterface { return githubinterceptor.NewInterceptor(sg) },
+	"gitlab":    func(sg staticSecret) triggersv1.InterceptorInterface { return gitlab.NewInterceptor(sg) },
+	"bitbucket": func(sg staticSecret) triggersv1.InterceptorInterface { return bitbucket.NewInterceptor(sg) },
+	"slack":     func(sg staticSecret) triggersv1.InterceptorInterface { return slack.NewInterceptor(sg) },
+}
+
+// staticSecret is the webhook secret every secretRef of a previewed interceptor
+// resolves to.
+type staticSecret []byte
+
+func (s staticSecret) Get(context.Context, string, *triggersv1.SecretRef) ([]byte, error) {
+	return s, nil
+}
+
+// PreviewConfig configures PreviewEvent.
+type PreviewConfig struct {
+	// Files are the YAML files, relative to the repository like the paths of
+	// oc.Create, holding the EventListener and the Triggers, TriggerBindings,
+	// ClusterTriggerBindings and TriggerTemplates it refers to. Other kinds,
+	// such as Pipelines and Secrets, are skipped.
+	Files []string
+	// EventListener names the listener to preview. Defaults to the only one in Files.
+	EventListener string
+	// Namespace is the namespace of the EventListener. Defaults to default.
+	Namespace string
+	// Interceptor and EventType add the headers MockPostEvent would send, see
+	// BuildHeaders. Without Interceptor the event has only a JSON Content-Type.
+	Interceptor string
+	EventType   string
+	// Payload is the event body, or PayloadFile the file holding it, e.g.
+	// testdata/push.json.
+	Payload     []byte
+	PayloadFile string
+	// Secret is what every secretRef of an interceptor resolves to. Defaults to
+	// config.TriggersSecretToken, the secret MockPostEvent signs with.
+	Secret string
+}
+
+// Preview is what an EventListener would do with one event.
+type Preview struct {
+	// EventID is the event ID $(context.eventID) resolves to.
+	EventID  string
+	Triggers []TriggerPreview
+}
+
+// TriggerPreview is the outcome of one Trigger of a Preview.
+type TriggerPreview struct {
+	Trigger string
+	// Stopped is the message of the interceptor that stopped the trigger; the
+	// trigger then has no Params or Resources.
+	Stopped string
+	// Extensions are the fields interceptors added, e.g. CEL overlays.
+	Extensions map[string]any
+	// Params are the resolved TriggerTemplate params.
+	Params []triggersv1.Param
+	// Resources are the rendered resource templates, labelled and namespaced as
+	// the EventListener would create them.
+	Resources []*unstructured.Unstructured
+}
+
+// Resources returns the resources of all triggers that were not stopped.
+func (p *Preview) Resources() []*unstructured.Unstructured {
+	var resources []*unstructured.Unstructured
+	for _, t := range p.Triggers {
+		resources = append(resources, t.Resources...)
+	}
+	return resources
+}
+
+// Param returns the value of the resolved param called name.
+func (t TriggerPreview) Param(name string) (string, bool) {
+	for _, p := range t.Params {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// PreviewEvent evaluates an event against an EventListener without a cluster: it
+// runs the interceptors of each trigger with the upstream Triggers
+// implementations, resolves the binding params with the TriggerTemplate
+// defaults and renders the resource templates, the way the EventListener sink
+// does before it creates them. It returns an error for anything that would stop
+// the sink with an error, such as a missing binding or a JSONPath that does not
+// match the payload. Webhook interceptors and custom ClusterInterceptors cannot
+// be previewed, and neither can trigger groups.
+func PreviewEvent(cfg PreviewConfig) (*Preview, error) {
+	cfg.Namespace = cmp.Or(cfg.Namespace, "default")
+	cfg.Secret = cmp.Or(cfg.Secret, config.TriggersSecretToken)
+	store, err := loadTriggerResources(cfg.Files)
+	if err != nil {
+		return nil, err
+	}
+	el, err := store.eventListener(cfg.EventListener)
+	if err != nil {
+		return nil, err
+	}
+	if len(el.Spec.TriggerGroups) > 0 {
+		return nil, fmt.Errorf("EventListener %s: trigger groups cannot be previewed", el.Name)
+	}
+	ts, err := store.triggersOf(el, cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := cfg.Payload
+	if cfg.PayloadFile != "" {
+		if payload, err = os.ReadFile(config.Path(cfg.PayloadFile)); err != nil {
+			return nil, fmt.Errorf("could not load payload: %w", err)
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://"+el.Name+"."+cfg.Namespace+".svc.cluster.local:8080", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Interceptor != "" {
+		strategy, ok := LookupInterceptor(cfg.Interceptor)
+		if !ok {
+			return nil, fmt.Errorf("unsupported interceptor type: %s, want one of %s", cfg.Interceptor, strings.Join(Interceptors(), ", "))
+		}
+		strategy(req, cfg.EventType, payload)
+	} else {
+		jsonHeaders(req)
+	}
+
+	preview := &Preview{EventID: uuid.NewString()}
+	for _, t := range ts {
+		tp, err := store.previewTrigger(t, req, payload, preview.EventID, el.Name, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("trigger %s: %w", t.Name, err)
+		}
+		preview.Triggers = append(preview.Triggers, tp)
+	}
+	return preview, nil
+}
+
+// previewTrigger follows processTrigger of the Triggers sink.
+func (s *triggerResources) previewTrigger(t *triggersv1.Trigger, req *http.Request, payload []byte, eventID, elName string, cfg PreviewConfig) (TriggerPreview, error) {
+	tp := TriggerPreview{Trigger: t.Name, Extensions: map[string]any{}}
+	for _, i := range t.Spec.Interceptors {
+		if i.Webhook != nil {
+			return tp, errors.New("webhook interceptors cannot be previewed")
+		}
+		newInterceptor, ok := clusterInterceptors[i.GetName()]
+		if !ok || (i.Ref.Kind != "" && i.Ref.Kind != triggersv1.ClusterInterceptorKind) {
+			return tp, fmt.Errorf("interceptor %s cannot be previewed, only the ClusterInterceptors Triggers ships", i.GetName())
+		}
+		resp := newInterceptor(staticSecret(cfg.Secret)).Process(context.Background(), &triggersv1.InterceptorRequest{
+			Body:              string(payload),
+			Header:            req.Header.Clone(),
+			Extensions:        tp.Extensions,
+			InterceptorParams: triggersinterceptors.GetInterceptorParams(i),
+			Context: &triggersv1.TriggerContext{
+				EventURL:  req.URL.String(),
+				EventID:   eventID,
+				TriggerID: fmt.Sprintf("namespaces/%s/triggers/%s", t.Namespace, t.Name),
+			},
+		})
+		if !resp.Continue {
+			tp.Stopped = resp.Status.Err().Error()
+			return tp, nil
+		}
+		for k, v := range resp.Extensions {
+			tp.Extensions[k] = v
+		}
+	}
+
+	rt, err := template.ResolveTrigger(*t, s.triggerBinding, s.clusterTriggerBinding, s.triggerTemplate)
+	if err != nil {
+		return tp, err
+	}
+	if tp.Params, err = template.ResolveParams(rt, payload, req.Header, tp.Extensions, template.NewTriggerContext(eventID)); err != nil {
+		return tp, err
+	}
+	for _, raw := range template.ResolveResources(rt.TriggerTemplate, tp.Params) {
+		resource := &unstructured.Unstructured{}
+		if err := resource.UnmarshalJSON(raw); err != nil {
+			return tp, fmt.Errorf("rendered resource template is not a resource: %w\n%s", err, raw)
+		}
+		resourceLabels := resource.GetLabels()
+		if resourceLabels == nil {
+			resourceLabels = map[string]string{}
+		}
+		resourceLabels[triggers.GroupName+triggers.EventListenerLabelKey] = elName
+		resourceLabels[triggers.GroupName+triggers.EventIDLabelKey] = eventID
+		resourceLabels[triggers.GroupName+triggers.TriggerLabelKey] = t.Name
+		resource.SetLabels(resourceLabels)
+		if resource.GetNamespace() == "" {
+			resource.SetNamespace(cfg.Namespace)
+		}
+		tp.Resources = append(tp.Resources, resource)
+	}
+	return tp, nil
+}
+
+// triggerResources are the Triggers resources of the preview files by name.
+type triggerResources struct {
+	eventListeners         map[string]*triggersv1.EventListener
+	triggers               map[string]*triggersv1.Trigger
+	triggerBindings        map[string]*triggersv1.TriggerBinding
+	clusterTriggerBindings map[string]*triggersv1.ClusterTriggerBinding
+	triggerTemplates       map[string]*triggersv1.TriggerTemplate
+}
+
+// loadTriggerResources reads every YAML document of files. TriggerBindings,
+// ClusterTriggerBindings and TriggerTemplates share their schema across API
+// versions and are read as v1beta1; EventListeners and Triggers must be v1beta1.
+func loadTriggerResources(files []string) (*triggerResources, error) {
+	s := &triggerResources{
+		eventListeners:         map[string]*triggersv1.EventListener{},
+		triggers:               map[string]*triggersv1.Trigger{},
+		triggerBindings:        map[string]*triggersv1.TriggerBinding{},
+		clusterTriggerBindings: map[string]*triggersv1.ClusterTriggerBinding{},
+		triggerTemplates:       map[string]*triggersv1.TriggerTemplate{},
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(config.Path(file))
+		if err != nil {
+			return nil, fmt.Errorf("could not load %s: %w", file, err)
+		}
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			var doc json.RawMessage
+			if err := decoder.Decode(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			if len(doc) == 0 || string(doc) == "null" {
+				continue
+			}
+			if err := s.add(doc); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *triggerResources) add(doc json.RawMessage) error {
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(doc, &meta); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(meta.APIVersion, triggers.GroupName+"/") {
+		return nil
+	}
+	v1beta1 := meta.APIVersion == triggersv1.SchemeGroupVersion.String()
+	switch meta.Kind {
+	case "EventListener":
+		if !v1beta1 {
+			return fmt.Errorf("EventListener must be %s, not %s", triggersv1.SchemeGroupVersion, meta.APIVersion)
+		}
+		return decodeInto(doc, s.eventListeners)
+	case "Trigger":
+		if !v1beta1 {
+			return fmt.Errorf("trigger must be %s, not %s", triggersv1.SchemeGroupVersion, meta.APIVersion)
+		}
+		return decodeInto(doc, s.triggers)
+	case "TriggerBinding":
+		return decodeInto(doc, s.triggerBindings)
+	case "ClusterTriggerBinding":
+		return decodeInto(doc, s.clusterTriggerBindings)
+	case "TriggerTemplate":
+		return decodeInto(doc, s.triggerTemplates)
+	}
+	return nil
+}
+
+// decodeInto decodes doc and adds it to byName under its name.
+func decodeInto[T any, PT interface {
+	*T
+	GetName() string
+}](doc json.RawMessage, byName map[string]PT) error {
+	obj := PT(new(T))
+	if err := json.Unmarshal(doc, obj); err != nil {
+		return err
+	}
+	byName[obj.GetName()] = obj
+	return nil
+}
+
+func (s *triggerResources) eventListener(name string) (*triggersv1.EventListener, error) {
+	if name != "" {
+		el, ok := s.eventListeners[name]
+		if !ok {
+			return nil, fmt.Errorf("no EventListener %s in the preview files", name)
+		}
+		return el, nil
+	}
+	if len(s.eventListeners) != 1 {
+		return nil, fmt.Errorf("the preview files hold %d EventListeners, name the one to preview", len(s.eventListeners))
+	}
+	for _, el := range s.eventListeners {
+		return el, nil
+	}
+	return nil, nil
+}
+
+// triggersOf returns the triggers of el: the Triggers its label selector
+// selects, then its triggers in order, as the sink merges them.
+func (s *triggerResources) triggersOf(el *triggersv1.EventListener, namespace string) ([]*triggersv1.Trigger, error) {
+	var ts []*triggersv1.Trigger
+	if el.Spec.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(el.Spec.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("EventListener %s: %w", el.Name, err)
+		}
+		for _, t := range s.triggers {
+			if selector.Matches(labels.Set(t.Labels)) {
+				ts = append(ts, t)
+			}
+		}
+	}
+	for _, et := range el.Spec.Triggers {
+		switch {
+		case et.Template == nil && et.TriggerRef != "":
+			t, ok := s.triggers[et.TriggerRef]
+			if !ok {
+				return nil, fmt.Errorf("EventListener %s: no Trigger %s in the preview files", el.Name, et.TriggerRef)
+			}
+			ts = append(ts, t)
+		case et.Template != nil:
+			ts = append(ts, &triggersv1.Trigger{
+				ObjectMeta: metav1.ObjectMeta{Name: et.Name},
+				Spec: triggersv1.TriggerSpec{
+					ServiceAccountName: et.ServiceAccountName,
+					Bindings:           et.Bindings,
+					Template:           *et.Template,
+					Interceptors:       et.Interceptors,
+				},
+			})
+		default:
+			return nil, fmt.Errorf("EventListener %s: trigger %q has neither a template nor a triggerRef", el.Name, et.Name)
+		}
+	}
+	for _, t := range ts {
+		t.Namespace = namespace
+	}
+	return ts, nil
+}
+
+func (s *triggerResources) triggerBinding(name string) (*triggersv1.TriggerBinding, error) {
+	if tb, ok := s.triggerBindings[name]; ok {
+		return tb, nil
+	}
+	return nil, fmt.Errorf("no TriggerBinding %s in the preview files", name)
+}
+
+func (s *triggerResources) clusterTriggerBinding(name string) (*triggersv1.ClusterTriggerBinding, error) {
+	if ctb, ok := s.clusterTriggerBindings[name]; ok {
+		return ctb, nil
+	}
+	return nil, fmt.Errorf("no ClusterTriggerBinding %s in the preview files, see testdata/triggers/clustertriggerbindings", name)
+}
+
+func (s *triggerResources) triggerTemplate(name string) (*triggersv1.TriggerTemplate, error) {
+	if tt, ok := s.triggerTemplates[name]; ok {
+		return tt, nil
+	}
+	return nil, fmt.Errorf("no TriggerTemplate %s in the preview files", name)
+}
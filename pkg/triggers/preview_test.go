@@ -0,0 +1,194 @@
+package triggers
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+func TestPreviewEvent(t *testing.T) {
+	push, err := os.ReadFile(config.Path("testdata/push.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctbPush, err := os.ReadFile(config.Path("testdata/triggers/github-ctb/push.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := os.ReadFile(config.Path("testdata/triggers/triggersCRD/pull-request.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prSHA := field(t, pr, "pull_request.head.sha").(string)
+
+	tests := []struct {
+		name string
+		cfg  PreviewConfig
+		// resources are the kind/name of the rendered resources.
+		resources []string
+		params    map[string]string
+	}{{
+		name: "embedded binding",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", "testdata/triggers/triggerbindings/triggerbinding.yaml", "testdata/triggers/triggertemplate/triggertemplate.yaml"},
+			Interceptor: "github", EventType: "push", PayloadFile: "testdata/push.json",
+		},
+		resources: []string{"PipelineRun/simple-pipeline-run"},
+		params: map[string]string{
+			"message":     "Hello from the Triggers EventListener(listener-embed-binding)!",
+			"contenttype": "application/json",
+			"gitrevision": field(t, push, "head_commit.id").(string),
+		},
+	}, {
+		name: "ClusterTriggerBinding",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/github-ctb/eventlistener-ctb-git-push.yaml", "testdata/triggers/github-ctb/Embeddedtriggertemplate-git-push.yaml", "testdata/triggers/clustertriggerbindings/github.yaml"},
+			Interceptor: "github", EventType: "push", PayloadFile: "testdata/triggers/github-ctb/push.json",
+		},
+		resources: []string{"PipelineRun/pipelinerun-git-push-ctb"},
+		params:    map[string]string{"git-revision": field(t, ctbPush, "head_commit.id").(string)},
+	}, {
+		name: "Trigger with CEL overlays",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/triggersCRD/eventlistener-triggerref.yaml", "testdata/triggers/triggersCRD/trigger.yaml", "testdata/triggers/triggersCRD/triggerbindings.yaml", "testdata/triggers/triggersCRD/triggertemplate.yaml", "testdata/triggers/triggersCRD/pipeline.yaml"},
+			Interceptor: "github", EventType: "pull_request", PayloadFile: "testdata/triggers/triggersCRD/pull-request.json",
+		},
+		resources: []string{"PipelineRun/parallel-pipelinerun"},
+		params:    map[string]string{"gitrevision": prSHA, "truncatedsha": prSHA[:7]},
+	}, {
+		name: "gitlab interceptor",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/gitlab/gitlab-push-listener.yaml"},
+			Interceptor: "gitlab", EventType: "Push Hook", PayloadFile: "testdata/triggers/gitlab/gitlab-push-event.json",
+		},
+		resources: []string{"PipelineRun/gitlab-run"},
+	}, {
+		name: "bitbucket interceptor",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/bitbucket/bitbucket-eventlistener-interceptor.yaml"},
+			Interceptor: "bitbucket", EventType: "refs_changed", PayloadFile: "testdata/triggers/bitbucket/refs-change-event.json",
+		},
+		resources: []string{"TaskRun/bitbucket-run"},
+	}, {
+		name: "CEL marshalJSON",
+		cfg: PreviewConfig{
+			Files:       []string{"testdata/triggers/triggerbindings/cel-marshalJson.yaml"},
+			Interceptor: "github", EventType: "push", PayloadFile: "testdata/push.json",
+		},
+		resources: []string{"TaskRun/cel-trig-marshaljson"},
+	}, {
+		name: "old escape annotation",
+		cfg: PreviewConfig{
+			Files:   []string{"testdata/triggers/triggerbindings/parse-json-body-with-annotation.yaml"},
+			Payload: []byte(`{"message":"hello"}`),
+		},
+		resources: []string{"TaskRun/trig-parse-json-body-with-annotation"},
+		params:    map[string]string{"body": `{"message":"hello"}`},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Namespace = "releasetest"
+			preview, err := PreviewEvent(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(preview.Triggers) != 1 || preview.Triggers[0].Stopped != "" {
+				t.Fatalf("Triggers = %+v, want one trigger that was not stopped", preview.Triggers)
+			}
+			var got []string
+			for _, r := range preview.Resources() {
+				got = append(got, r.GetKind()+"/"+r.GetName())
+				if r.GetNamespace() != "releasetest" || r.GetLabels()["triggers.tekton.dev/triggers-eventid"] != preview.EventID {
+					t.Errorf("%s/%s is not namespaced and labelled as the EventListener creates it: %s %v", r.GetKind(), r.GetName(), r.GetNamespace(), r.GetLabels())
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.resources, ",") {
+				t.Fatalf("Resources() = %v, want %v", got, tt.resources)
+			}
+			for name, want := range tt.params {
+				if got, _ := preview.Triggers[0].Param(name); got != want {
+					t.Errorf("param %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreviewEventRendersParams(t *testing.T) {
+	preview, err := PreviewEvent(PreviewConfig{
+		Files:       []string{"testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", "testdata/triggers/triggerbindings/triggerbinding.yaml", "testdata/triggers/triggertemplate/triggertemplate.yaml"},
+		Interceptor: "github", EventType: "push", Payload: MustNewEvent(GitHub, Push, EventParams{Repo: "tektoncd/triggers"}).Payload,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := preview.Resources()
+	if len(resources) != 1 {
+		t.Fatalf("Resources() = %v, want one PipelineRun", resources)
+	}
+	params, _, _ := unstructured.NestedSlice(resources[0].Object, "spec", "params")
+	want := map[string]string{"message": "Hello from the Triggers EventListener(listener-embed-binding)!", "contenttype": "application/json"}
+	for _, p := range params {
+		p := p.(map[string]any)
+		if p["value"] != want[p["name"].(string)] {
+			t.Errorf("PipelineRun param %s = %v, want %q", p["name"], p["value"], want[p["name"].(string)])
+		}
+	}
+}
+
+func TestPreviewEventStopsAndFails(t *testing.T) {
+	triggerRef := []string{"testdata/triggers/triggersCRD/eventlistener-triggerref.yaml", "testdata/triggers/triggersCRD/trigger.yaml", "testdata/triggers/triggersCRD/triggerbindings.yaml", "testdata/triggers/triggersCRD/triggertemplate.yaml"}
+	preview, err := PreviewEvent(PreviewConfig{Files: triggerRef, Interceptor: "github", EventType: "push", PayloadFile: "testdata/triggers/triggersCRD/pull-request.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped := preview.Triggers[0].Stopped; !strings.Contains(stopped, "did not return true") || len(preview.Resources()) != 0 {
+		t.Fatalf("Triggers = %+v, want the CEL filter to stop the push event", preview.Triggers)
+	}
+
+	preview, err = PreviewEvent(PreviewConfig{Files: []string{"testdata/triggers/gitlab/gitlab-push-listener.yaml"}, Interceptor: "gitlab", EventType: "Push Hook", PayloadFile: "testdata/triggers/gitlab/gitlab-push-event.json", Secret: "not-the-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Triggers[0].Stopped == "" {
+		t.Fatal("gitlab interceptor accepted a token that does not match its secret")
+	}
+
+	tests := []struct {
+		name    string
+		cfg     PreviewConfig
+		wantErr string
+	}{{
+		name:    "missing TriggerBinding",
+		cfg:     PreviewConfig{Files: []string{"testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", "testdata/triggers/triggertemplate/triggertemplate.yaml"}, Payload: []byte(`{}`)},
+		wantErr: "no TriggerBinding pipeline-binding",
+	}, {
+		name:    "missing ClusterTriggerBinding",
+		cfg:     PreviewConfig{Files: []string{"testdata/triggers/github-ctb/eventlistener-ctb-git-push.yaml", "testdata/triggers/github-ctb/Embeddedtriggertemplate-git-push.yaml"}, Payload: []byte(`{}`)},
+		wantErr: "no ClusterTriggerBinding github-push",
+	}, {
+		name:    "JSONPath not in payload",
+		cfg:     PreviewConfig{Files: []string{"testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", "testdata/triggers/triggerbindings/triggerbinding.yaml", "testdata/triggers/triggertemplate/triggertemplate.yaml"}, Payload: []byte(`{"ref":"refs/heads/main"}`)},
+		wantErr: "failed to replace JSONPath value for param gitrevision",
+	}, {
+		name:    "two EventListeners",
+		cfg:     PreviewConfig{Files: []string{"testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", "testdata/triggers/eventlisteners/eventlistener-embeded-binding-2.yaml"}},
+		wantErr: "hold 2 EventListeners",
+	}, {
+		name:    "missing file",
+		cfg:     PreviewConfig{Files: []string{"testdata/triggers/missing.yaml"}},
+		wantErr: "could not load testdata/triggers/missing.yaml",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PreviewEvent(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("PreviewEvent() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
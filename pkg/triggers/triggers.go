@@ -31,7 +31,7 @@ import (
 
 func getServiceNameAndPort(c *clients.Clients, elname, namespace string) (string, string) {
 	// Verify the EventListener to be ready
-	err := wait.WaitFor(c.Ctx, wait.EventListenerReady(c, namespace, elname))
+	err := wait.WaitForEventListenerReady(c, namespace, elname)
 	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("event listener %s in namespace %s not ready", elname, namespace))
 
 	labelSelector := fields.SelectorFromSet(resources.GenerateLabels(elname, resources.DefaultStaticResourceLabels)).String()
@@ -142,14 +142,24 @@ func MockPostEventWithEmptyPayload(routeurl string) *http.Response {
 // MockPostEvent sends a POST request to the EventListener sink with interceptor-specific headers.
 // It returns both the HTTP response and the payload bytes (for downstream use).
 func MockPostEvent(routeurl, interceptor, eventType, payload string, isTLS bool) (*http.Response, []byte) {
+	eventBodyJSON, err := os.ReadFile(resource.Path(payload))
+	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("could not load test data from file %s", payload))
+
+	return postEvent(routeurl, interceptor, eventType, eventBodyJSON, isTLS), eventBodyJSON
+}
+
+// MockPostGeneratedEvent sends an event built by NewEvent to the EventListener
+// sink with the headers and signature of its provider.
+func MockPostGeneratedEvent(routeurl string, event *Event, isTLS bool) *http.Response {
+	return postEvent(routeurl, string(event.Provider), event.Type, event.Payload, isTLS)
+}
+
+func postEvent(routeurl, interceptor, eventType string, eventBodyJSON []byte, isTLS bool) *http.Response {
 	var (
 		req  *http.Request
 		err  error
 		resp *http.Response
 	)
-	eventBodyJSON, err := os.ReadFile(resource.Path(payload))
-	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("could not load test data from file %s", payload))
-
 	// Send POST request to EventListener sink
 	if isTLS {
 		req, err = http.NewRequest("POST", "https://"+strings.Split(routeurl, "//")[1], bytes.NewBuffer(eventBodyJSON))
@@ -169,11 +179,28 @@ func MockPostEvent(routeurl, interceptor, eventType, payload string, isTLS bool)
 
 	Expect(resp.StatusCode).To(BeNumerically("<=", http.StatusAccepted),
 		fmt.Sprintf("sink did not return 2xx response. Got status code: %d", resp.StatusCode))
-	return resp, eventBodyJSON
+	return resp
 }
 
 // AssertElResponse asserts the EventListener response body and checks sink pod logs for errors.
 func AssertElResponse(c *clients.Clients, resp *http.Response, elname, namespace string) {
+	AssertSinkResponse(resp, elname, namespace)
+
+	labelSelector := fields.SelectorFromSet(resources.GenerateLabels(elname, resources.DefaultStaticResourceLabels)).String()
+	// Grab EventListener sink pods
+	sinkPods, err := c.KubeClient.Kube.CoreV1().Pods(namespace).List(c.Ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("failed to list event listener sink pods with label selector %s in namespace %s", labelSelector, namespace))
+
+	logs := cmd.MustSucceed("oc", "-n", namespace, "logs", "pods/"+sinkPods.Items[0].Name, "--all-containers", "--tail=2").Stdout()
+	if strings.Contains(logs, "error") {
+		GinkgoWriter.Printf("Error: sink logs: \n %s", logs)
+		Fail(fmt.Sprintf("Error: sink logs: \n %s", logs))
+	}
+}
+
+// AssertSinkResponse asserts the EventListener response body names elname in
+// namespace and carries an event ID, and returns it.
+func AssertSinkResponse(resp *http.Response, elname, namespace string) sink.Response {
 	wantBody := sink.Response{
 		EventListener: elname,
 		Namespace:     namespace,
@@ -189,17 +216,7 @@ func AssertElResponse(c *clients.Clients, resp *http.Response, elname, namespace
 	}
 
 	Expect(gotBody.EventID).NotTo(BeEmpty(), "sink response has no eventID")
-
-	labelSelector := fields.SelectorFromSet(resources.GenerateLabels(elname, resources.DefaultStaticResourceLabels)).String()
-	// Grab EventListener sink pods
-	sinkPods, err := c.KubeClient.Kube.CoreV1().Pods(namespace).List(c.Ctx, metav1.ListOptions{LabelSelector: labelSelector})
-	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("failed to list event listener sink pods with label selector %s in namespace %s", labelSelector, namespace))
-
-	logs := cmd.MustSucceed("oc", "-n", namespace, "logs", "pods/"+sinkPods.Items[0].Name, "--all-containers", "--tail=2").Stdout()
-	if strings.Contains(logs, "error") {
-		GinkgoWriter.Printf("Error: sink logs: \n %s", logs)
-		Fail(fmt.Sprintf("Error: sink logs: \n %s", logs))
-	}
+	return gotBody
 }
 
 // CleanupTriggers deletes an EventListener and waits for generated resources to be removed.
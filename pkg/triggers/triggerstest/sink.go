@@ -0,0 +1,178 @@
+// Package triggerstest provides an in-process EventListener sink for unit tests
+// of the event helpers in pkg/triggers.
+package triggerstest
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1beta1"
+	"github.com/tektoncd/triggers/pkg/interceptors/bitbucket"
+	githubinterceptor "github.com/tektoncd/triggers/pkg/interceptors/github"
+	"github.com/tektoncd/triggers/pkg/interceptors/gitlab"
+	"github.com/tektoncd/triggers/pkg/sink"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// coreInterceptors maps the interceptor names of triggers.BuildHeaders to the
+// Tekton core interceptor that verifies them. Gitea and Forgejo send GitHub compatible
+// headers, and Bitbucket Cloud signs like Bitbucket Server.
+var coreInterceptors = map[string]func(sg staticSecret) triggersv1.InterceptorInterface{
+	"github":          func(sg staticSecret) triggersv1.InterceptorInterface { return githubinterceptor.NewInterceptor(sg) },
+	"gitea":           func(sg staticSecret) triggersv1.InterceptorInterface { return githubinterceptor.NewInterceptor(sg) },
+	"forgejo":         func(sg staticSecret) triggersv1.InterceptorInterface { return githubinterceptor.NewInterceptor(sg) },
+	"gitlab":          func(sg staticSecret) triggersv1.InterceptorInterface { return gitlab.NewInterceptor(sg) },
+	"bitbucket":       func(sg staticSecret) triggersv1.InterceptorInterface { return bitbucket.NewInterceptor(sg) },
+	"bitbucket-cloud": func(sg staticSecret) triggersv1.InterceptorInterface { return bitbucket.NewInterceptor(sg) },
+}
+
+// staticSecret is the webhook secret every secretRef of the fake sink resolves to.
+type staticSecret []byte
+
+func (s staticSecret) Get(context.Context, string, *triggersv1.SecretRef) ([]byte, error) {
+	return s, nil
+}
+
+// SinkConfig configures NewSink.
+type SinkConfig struct {
+	// EventListener and Namespace are echoed in the sink response. They default
+	// to fake-listener and default.
+	EventListener string
+	Namespace     string
+	// Interceptor names the core interceptor that checks every event, as
+	// triggers.MockPostEvent names it: github, gitlab, bitbucket,
+	// bitbucket-cloud, gitea or forgejo, case-insensitively. Empty accepts
+	// every event, like a trigger without interceptors.
+	Interceptor string
+	// EventTypes is the eventTypes param of the interceptor; nil allows any.
+	EventTypes []string
+	// Secret is the value of the interceptor's secretRef. Defaults to
+	// config.TriggersSecretToken, the secret the suites create.
+	Secret string
+}
+
+// ReceivedEvent is one request the fake sink handled.
+type ReceivedEvent struct {
+	ID     string
+	Header http.Header
+	Body   []byte
+	// Accepted is false when the interceptor stopped the trigger; Reason then
+	// holds the message the EventListener would log.
+	Accepted bool
+	Reason   string
+}
+
+// Sink is an in-process stand-in for an EventListener sink. Like the real
+// sink it answers every readable request with 202 Accepted and a sink.Response,
+// while the interceptor verdict only shows up afterwards; here in Events and
+// Rejected rather than in the sink pod logs. Unlike the real sink, the
+// interceptor runs before the response is written, so the verdict is recorded
+// by the time triggers.MockPostEvent returns. No triggers are processed.
+type Sink struct {
+	// URL is the route URL to pass to triggers.MockPostEvent.
+	URL string
+
+	cfg         SinkConfig
+	uid         string
+	interceptor triggersv1.InterceptorInterface
+
+	mu     sync.Mutex
+	events []ReceivedEvent
+}
+
+// NewSink starts a fake sink that is closed when the test ends. It fails
+// the test for an interceptor without a core implementation.
+func NewSink(t testing.TB, cfg SinkConfig) *Sink {
+	t.Helper()
+	cfg.EventListener = cmp.Or(cfg.EventListener, "fake-listener")
+	cfg.Namespace = cmp.Or(cfg.Namespace, "default")
+	cfg.Secret = cmp.Or(cfg.Secret, config.TriggersSecretToken)
+	s := &Sink{cfg: cfg, uid: uuid.NewString()}
+	if cfg.Interceptor != "" {
+		newInterceptor, ok := coreInterceptors[strings.ToLower(cfg.Interceptor)]
+		if !ok {
+			t.Fatalf("fake sink: no core interceptor verifies %q", cfg.Interceptor)
+		}
+		s.interceptor = newInterceptor(staticSecret(cfg.Secret))
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	s.URL = server.URL
+	return s
+}
+
+// ServeHTTP handles one event the way the EventListener sink does.
+func (s *Sink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	event := ReceivedEvent{ID: uuid.NewString(), Header: r.Header.Clone(), Body: body, Accepted: true}
+	if s.interceptor != nil {
+		resp := s.interceptor.Process(r.Context(), s.interceptorRequest(r, event))
+		if !resp.Continue {
+			event.Accepted = false
+			event.Reason = fmt.Sprintf("interceptor stopped trigger processing: %s", resp.Status.Err())
+		}
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(sink.Response{
+		EventListener:    s.cfg.EventListener,
+		EventListenerUID: s.uid,
+		Namespace:        s.cfg.Namespace,
+		EventID:          event.ID,
+	})
+}
+
+func (s *Sink) interceptorRequest(r *http.Request, event ReceivedEvent) *triggersv1.InterceptorRequest {
+	params := map[string]any{
+		"secretRef": &triggersv1.SecretRef{SecretName: "fake-sink-secret", SecretKey: "secretToken"},
+	}
+	if s.cfg.EventTypes != nil {
+		params["eventTypes"] = s.cfg.EventTypes
+	}
+	return &triggersv1.InterceptorRequest{
+		Body:              string(event.Body),
+		Header:            event.Header,
+		InterceptorParams: params,
+		Context: &triggersv1.TriggerContext{
+			EventURL:  r.URL.String(),
+			EventID:   event.ID,
+			TriggerID: fmt.Sprintf("namespaces/%s/triggers/%s", s.cfg.Namespace, s.cfg.EventListener),
+		},
+	}
+}
+
+// Events returns every event received so far, in arrival order.
+func (s *Sink) Events() []ReceivedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ReceivedEvent(nil), s.events...)
+}
+
+// Rejected returns the events the interceptor stopped.
+func (s *Sink) Rejected() []ReceivedEvent {
+	var rejected []ReceivedEvent
+	for _, event := range s.Events() {
+		if !event.Accepted {
+			rejected = append(rejected, event)
+		}
+	}
+	return rejected
+}
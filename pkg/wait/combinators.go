@@ -0,0 +1,163 @@
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
+)
+
+// Condition is implemented by both kinds of poll conditions in this package, so
+// the combinators below work with either:
+//
+//	wait.AnyOf(wait.Succeed(name), wait.FailedWithReason("PipelineRunTimeout", name))
+//	wait.Stable(30*time.Second, wait.Running(name))
+//	wait.WithinDuration(time.Minute, wait.EventListenerReady(c, ns, name))
+//
+// A condition returns (true, nil) once it is met, (false, nil) while it may still
+// be met, and an error once it never can be, e.g. a Succeed condition seeing a
+// failed run. Combinators rely on that error to fail fast instead of polling
+// until config.APITimeout.
+type Condition interface {
+	ConditionAccessorFn | wait.ConditionFunc
+}
+
+// AnyOf is met as soon as one of conds is met. It fails once every condition has
+// failed, with all of their errors.
+func AnyOf[F Condition](conds ...F) F {
+	fns := lift(conds)
+	return lower[F](func(ca apis.ConditionAccessor) (bool, error) {
+		var errs []error
+		for _, fn := range fns {
+			done, err := fn(ca)
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+			case done:
+				return true, nil
+			}
+		}
+		if len(errs) > 0 && len(errs) == len(fns) {
+			return true, fmt.Errorf("none of the conditions can be met: %w", errors.Join(errs...))
+		}
+		return false, nil
+	})
+}
+
+// AllOf is met once every one of conds is met at the same time. It fails as soon
+// as any condition fails.
+func AllOf[F Condition](conds ...F) F {
+	fns := lift(conds)
+	return lower[F](func(ca apis.ConditionAccessor) (bool, error) {
+		all := true
+		for _, fn := range fns {
+			done, err := fn(ca)
+			if err != nil {
+				return true, err
+			}
+			all = all && done
+		}
+		return all, nil
+	})
+}
+
+// Not is met whenever cond is not, including when cond has failed: Not(Running(name))
+// holds before the run starts and after it finished.
+func Not[F Condition](cond F) F {
+	fn := lift([]F{cond})[0]
+	return lower[F](func(ca apis.ConditionAccessor) (bool, error) {
+		done, err := fn(ca)
+		return err != nil || !done, nil
+	})
+}
+
+// Stable is met once cond has been met on every evaluation for at least d, e.g.
+// "running for at least 30s" or "succeeded and stayed succeeded". A failure of
+// cond fails immediately. Stable keeps state between calls, so build a new one
+// for each wait.
+func Stable[F Condition](d time.Duration, cond F) F {
+	fn := lift([]F{cond})[0]
+	var since time.Time
+	return lower[F](func(ca apis.ConditionAccessor) (bool, error) {
+		done, err := fn(ca)
+		if err != nil {
+			return true, err
+		}
+		if !done {
+			since = time.Time{}
+			return false, nil
+		}
+		if since.IsZero() {
+			since = time.Now()
+		}
+		return time.Since(since) >= d, nil
+	})
+}
+
+// WithinDuration fails when cond is not met within d of its first evaluation,
+// bounding one step of a wait more tightly than config.APITimeout. It keeps
+// state between calls, so build a new one for each wait.
+func WithinDuration[F Condition](d time.Duration, cond F) F {
+	fn := lift([]F{cond})[0]
+	var start time.Time
+	return lower[F](func(ca apis.ConditionAccessor) (bool, error) {
+		if start.IsZero() {
+			start = time.Now()
+		}
+		done, err := fn(ca)
+		if done || err != nil {
+			return done, err
+		}
+		if elapsed := time.Since(start); elapsed >= d {
+			return true, fmt.Errorf("condition not met within %s", d)
+		}
+		return false, nil
+	})
+}
+
+// FailFast fails cond as soon as the resource reached a terminal Succeeded=False
+// state that cond does not accept, instead of waiting for config.APITimeout.
+// Succeed, Failed, FailedWithReason, FailedWithMessage and Running already fail
+// fast; use it for custom conditions.
+func FailFast(name string, cond ConditionAccessorFn) ConditionAccessorFn {
+	return func(ca apis.ConditionAccessor) (bool, error) {
+		done, err := cond(ca)
+		if done || err != nil {
+			return done, err
+		}
+		if c := ca.GetCondition(apis.ConditionSucceeded); c != nil && c.Status == corev1.ConditionFalse {
+			return true, fmt.Errorf("%q failed with reason %q: %s", name, c.Reason, c.Message)
+		}
+		return false, nil
+	}
+}
+
+// lift converts conditions to ConditionAccessorFns; a wait.ConditionFunc ignores
+// the accessor.
+func lift[F Condition](conds []F) []ConditionAccessorFn {
+	fns := make([]ConditionAccessorFn, len(conds))
+	for i, cond := range conds {
+		switch fn := any(cond).(type) {
+		case ConditionAccessorFn:
+			fns[i] = fn
+		case wait.ConditionFunc:
+			fns[i] = func(apis.ConditionAccessor) (bool, error) { return fn() }
+		}
+	}
+	return fns
+}
+
+// lower converts a combined ConditionAccessorFn back to the caller's condition type.
+func lower[F Condition](fn ConditionAccessorFn) F {
+	var out F
+	switch p := any(&out).(type) {
+	case *ConditionAccessorFn:
+		*p = fn
+	case *wait.ConditionFunc:
+		*p = func() (bool, error) { return fn(nil) }
+	}
+	return out
+}
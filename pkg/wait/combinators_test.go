@@ -0,0 +1,96 @@
+package wait
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func status(s corev1.ConditionStatus, reason string) *duckv1.Status {
+	st := succeeded(s, reason, "")
+	return &st
+}
+
+func TestAnyOfFailsOnlyWhenNoConditionCanBeMet(t *testing.T) {
+	cond := AnyOf(Succeed("build"), FailedWithReason("PipelineRunTimeout", "build"))
+
+	if done, err := cond(status(corev1.ConditionUnknown, "Running")); done || err != nil {
+		t.Fatalf("running: got (%v, %v), want to keep waiting", done, err)
+	}
+	if done, err := cond(status(corev1.ConditionFalse, "PipelineRunTimeout")); !done || err != nil {
+		t.Fatalf("timed out: got (%v, %v), want met", done, err)
+	}
+	done, err := cond(status(corev1.ConditionFalse, "Failed"))
+	if !done || err == nil || !strings.Contains(err.Error(), "none of the conditions can be met") {
+		t.Fatalf("failed: got (%v, %v), want a fast failure", done, err)
+	}
+}
+
+func TestAllOfFailsFast(t *testing.T) {
+	cond := AllOf(Not(Running("build")), Succeed("build"))
+	if done, err := cond(status(corev1.ConditionTrue, "Succeeded")); !done || err != nil {
+		t.Fatalf("succeeded: got (%v, %v), want met", done, err)
+	}
+	if done, err := cond(status(corev1.ConditionFalse, "Failed")); !done || err == nil {
+		t.Fatalf("failed: got (%v, %v), want the Succeed error", done, err)
+	}
+}
+
+func TestStableRequiresConditionToHold(t *testing.T) {
+	cond := Stable(50*time.Millisecond, Running("build"))
+	running := status(corev1.ConditionUnknown, "Running")
+
+	if done, _ := cond(running); done {
+		t.Fatal("met on the first evaluation")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if done, _ := cond(status(corev1.ConditionUnknown, "")); done {
+		t.Fatal("met although the condition stopped holding")
+	}
+	if done, _ := cond(running); done {
+		t.Fatal("met without restarting the window")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if done, err := cond(running); !done || err != nil {
+		t.Fatalf("got (%v, %v), want met after holding for the window", done, err)
+	}
+	if done, err := cond(status(corev1.ConditionTrue, "Succeeded")); !done || err == nil {
+		t.Fatalf("finished: got (%v, %v), want the Running error", done, err)
+	}
+}
+
+func TestWithinDurationOnConditionFunc(t *testing.T) {
+	calls := 0
+	var never wait.ConditionFunc = func() (bool, error) {
+		calls++
+		return false, nil
+	}
+	cond := WithinDuration(20*time.Millisecond, never)
+	if done, err := cond(); done || err != nil {
+		t.Fatalf("got (%v, %v), want to keep waiting", done, err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if done, err := cond(); !done || err == nil || !strings.Contains(err.Error(), "not met within 20ms") {
+		t.Fatalf("got (%v, %v), want a deadline error", done, err)
+	}
+	if calls != 2 {
+		t.Fatalf("condition evaluated %d times, want 2", calls)
+	}
+}
+
+func TestFailFastAbortsCustomCondition(t *testing.T) {
+	var neverMet ConditionAccessorFn = func(apis.ConditionAccessor) (bool, error) { return false, nil }
+	cond := FailFast("build", neverMet)
+	if done, err := cond(status(corev1.ConditionUnknown, "Running")); done || err != nil {
+		t.Fatalf("running: got (%v, %v), want to keep waiting", done, err)
+	}
+	done, err := cond(status(corev1.ConditionFalse, "CouldntGetTask"))
+	if !done || err == nil || !strings.Contains(err.Error(), `"CouldntGetTask"`) {
+		t.Fatalf("failed: got (%v, %v), want a fast failure naming the reason", done, err)
+	}
+}
@@ -0,0 +1,184 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	triggersv1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	eventReconciler "github.com/tektoncd/triggers/pkg/reconciler/eventlistener"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/k8s"
+)
+
+const (
+	// explainTimeout bounds the API calls made to explain a timeout.
+	explainTimeout = 30 * time.Second
+
+	// maxExplainEvents caps the warning events listed in a TimeoutError.
+	maxExplainEvents = 10
+
+	// maxExplainLineLength caps each rendered condition or event message.
+	maxExplainLineLength = 200
+)
+
+// Section is a titled group of lines rendered under a TimeoutError.
+type Section struct {
+	Title string
+	Lines []string
+}
+
+// renderTree appends sections to sb as a compact tree.
+func renderTree(sb *strings.Builder, sections []Section) {
+	for i, section := range sections {
+		branch, indent := "├─ ", "│  "
+		if i == len(sections)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		fmt.Fprintf(sb, "\n%s%s", branch, section.Title)
+		for j, line := range section.Lines {
+			leaf := "├─ "
+			if j == len(section.Lines)-1 {
+				leaf = "└─ "
+			}
+			fmt.Fprintf(sb, "\n%s%s%s", indent, leaf, line)
+		}
+	}
+}
+
+// conditionLines renders status.conditions of obj, one condition per line.
+func conditionLines(obj runtime.Object) []string {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+	lines := k8s.ConditionLines(content)
+	for i, line := range lines {
+		lines[i] = truncateLine(line)
+	}
+	return lines
+}
+
+// truncateLine keeps rendered lines to a single, bounded line.
+func truncateLine(line string) string {
+	line = strings.Join(strings.Fields(line), " ")
+	if len(line) > maxExplainLineLength {
+		return line[:maxExplainLineLength] + "..."
+	}
+	return line
+}
+
+// explainPipelineRun adds the child TaskRun states and recent warning events to a
+// PipelineRun timeout. Other errors are returned unchanged.
+func explainPipelineRun(c *clients.Clients, err error) error {
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		return err
+	}
+	pr, ok := timeout.LastObserved.(*pipelinev1.PipelineRun)
+	if !ok {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	names := []string{pr.Name}
+	var taskRuns []string
+	for _, child := range pr.Status.ChildReferences {
+		if child.Kind != "TaskRun" {
+			continue
+		}
+		tr, getErr := c.Tekton.TektonV1().TaskRuns(pr.Namespace).Get(ctx, child.Name, metav1.GetOptions{})
+		if getErr != nil {
+			taskRuns = append(taskRuns, truncateLine(fmt.Sprintf("%s (%s): %v", child.Name, child.PipelineTaskName, getErr)))
+			continue
+		}
+		taskRuns = append(taskRuns, fmt.Sprintf("%s (%s): %s", tr.Name, child.PipelineTaskName, succeededCondition(&tr.Status)))
+		names = append(names, tr.Name)
+		if tr.Status.PodName != "" {
+			names = append(names, tr.Status.PodName)
+		}
+	}
+	if len(taskRuns) > 0 {
+		timeout.Sections = append(timeout.Sections, Section{Title: "TaskRuns", Lines: taskRuns})
+	}
+	timeout.Sections = append(timeout.Sections, warningEvents(ctx, c, pr.Namespace, names...))
+	return err
+}
+
+// explainTaskRun adds the recent warning events of a TaskRun and its pod to a
+// TaskRun timeout. Other errors are returned unchanged.
+func explainTaskRun(c *clients.Clients, err error) error {
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		return err
+	}
+	tr, ok := timeout.LastObserved.(*pipelinev1.TaskRun)
+	if !ok {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+	names := []string{tr.Name}
+	if tr.Status.PodName != "" {
+		names = append(names, tr.Status.PodName)
+	}
+	timeout.Sections = append(timeout.Sections, warningEvents(ctx, c, tr.Namespace, names...))
+	return err
+}
+
+// explainEventListener adds the recent warning events of an EventListener and its
+// generated deployment to an EventListener timeout. Other errors are returned unchanged.
+func explainEventListener(c *clients.Clients, err error) error {
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		return err
+	}
+	el, ok := timeout.LastObserved.(*triggersv1alpha1.EventListener)
+	if !ok {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+	timeout.Sections = append(timeout.Sections, warningEvents(ctx, c, el.Namespace,
+		el.Name, fmt.Sprintf("%s-%s", eventReconciler.GeneratedResourcePrefix, el.Name)))
+	return err
+}
+
+// succeededCondition renders the Succeeded condition of a Tekton status.
+func succeededCondition(ca apis.ConditionAccessor) string {
+	c := ca.GetCondition(apis.ConditionSucceeded)
+	if c == nil {
+		return "no Succeeded condition yet"
+	}
+	return truncateLine(k8s.FormatCondition(string(c.Type), string(c.Status), c.Reason, c.Message))
+}
+
+// warningEvents lists the latest warning events for the named objects.
+func warningEvents(ctx context.Context, c *clients.Clients, namespace string, names ...string) Section {
+	section := Section{Title: "warning events"}
+	events, err := k8s.GetWarningEventsFor(ctx, c, namespace, names...)
+	if err != nil {
+		section.Lines = []string{fmt.Sprintf("[error listing events: %v]", err)}
+		return section
+	}
+	if len(events) == 0 {
+		section.Title = "warning events: none"
+		return section
+	}
+	if len(events) > maxExplainEvents {
+		events = events[len(events)-maxExplainEvents:]
+	}
+	for _, event := range events {
+		section.Lines = append(section.Lines, truncateLine(fmt.Sprintf("%s/%s %s: %s",
+			event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, event.Message)))
+	}
+	return section
+}
@@ -0,0 +1,122 @@
+package wait
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonfake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+)
+
+func runtimeTypeMeta(kind string) runtime.TypeMeta {
+	return runtime.TypeMeta{APIVersion: "tekton.dev/v1", Kind: kind}
+}
+
+func succeeded(status corev1.ConditionStatus, reason, message string) duckv1.Status {
+	return duckv1.Status{Conditions: duckv1.Conditions{{
+		Type: apis.ConditionSucceeded, Status: status, Reason: reason, Message: message,
+	}}}
+}
+
+// eventServer serves a fixed warning event list to a real Kubernetes clientset.
+func eventServer(t *testing.T, events ...corev1.Event) *kubernetes.Clientset {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/demo/events" || r.URL.Query().Get("fieldSelector") != "type=Warning" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(corev1.EventList{
+			TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "v1"},
+			Items:    events,
+		})
+	}))
+	t.Cleanup(server.Close)
+	kube, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kube
+}
+
+func TestPipelineRunTimeoutExplainsChildrenAndEvents(t *testing.T) {
+	pr := &pipelinev1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "demo"},
+		Status: pipelinev1.PipelineRunStatus{
+			Status: succeeded(corev1.ConditionUnknown, "Running", "Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0"),
+			PipelineRunStatusFields: pipelinev1.PipelineRunStatusFields{ChildReferences: []pipelinev1.ChildStatusReference{
+				{TypeMeta: runtimeTypeMeta("TaskRun"), Name: "build-fetch", PipelineTaskName: "fetch"},
+				{TypeMeta: runtimeTypeMeta("TaskRun"), Name: "build-compile", PipelineTaskName: "compile"},
+			}},
+		},
+	}
+	fetch := &pipelinev1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-fetch", Namespace: "demo"},
+		Status:     pipelinev1.TaskRunStatus{Status: succeeded(corev1.ConditionTrue, "Succeeded", "All Steps have completed executing")},
+	}
+	compile := &pipelinev1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-compile", Namespace: "demo"},
+		Status: pipelinev1.TaskRunStatus{
+			Status:              succeeded(corev1.ConditionUnknown, "Pending", "pod status \"PodScheduled\":\"False\""),
+			TaskRunStatusFields: pipelinev1.TaskRunStatusFields{PodName: "build-compile-pod"},
+		},
+	}
+	now := time.Now()
+	c := &clients.Clients{
+		Tekton: tektonfake.NewSimpleClientset(fetch, compile),
+		KubeClient: &clients.KubeClient{Kube: eventServer(t,
+			corev1.Event{
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "build-compile-pod"},
+				Reason:         "FailedScheduling",
+				Message:        "0/3 nodes are available",
+				LastTimestamp:  metav1.NewTime(now),
+			},
+			corev1.Event{
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "unrelated"},
+				Reason:         "BackOff",
+				LastTimestamp:  metav1.NewTime(now),
+			},
+		)},
+	}
+
+	err := explainPipelineRun(c, &TimeoutError{
+		Kind: "PipelineRun", Name: "build", Desc: "PipelineRunCompleted",
+		Waited: 10 * time.Minute, LastObserved: pr, Cause: context.DeadlineExceeded,
+	})
+	want := `timed out after 10m0s waiting for PipelineRun "build" (PipelineRunCompleted): context deadline exceeded
+├─ conditions
+│  └─ Succeeded=Unknown reason=Running: Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0
+├─ TaskRuns
+│  ├─ build-fetch (fetch): Succeeded=True reason=Succeeded: All Steps have completed executing
+│  └─ build-compile (compile): Succeeded=Unknown reason=Pending: pod status "PodScheduled":"False"
+└─ warning events
+   └─ Pod/build-compile-pod FailedScheduling: 0/3 nodes are available`
+	if got := err.Error(); got != want {
+		t.Fatalf("unexpected explanation:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTimeoutWithoutConditionsShowsSnapshot(t *testing.T) {
+	err := &TimeoutError{
+		Kind: "Pod", Name: "build", Desc: "PodRunning", Cause: context.DeadlineExceeded,
+		LastObserved: pod(corev1.PodPending),
+	}
+	if msg := err.Error(); !strings.Contains(msg, "└─ last observed state\n   ├─ status:\n   └─   phase: Pending") {
+		t.Fatalf("unexpected rendering:\n%s", msg)
+	}
+}
@@ -0,0 +1,289 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+)
+
+// maxSnapshotLines caps the last observed state rendered into a TimeoutError.
+const maxSnapshotLines = 40
+
+// Predicate reports whether obj reached the awaited state. Returning an error
+// stops the wait immediately, e.g. when a resource reached the wrong terminal state.
+type Predicate[T runtime.Object] func(obj T) (bool, error)
+
+// ResourceClient fetches a single named resource. Every typed client-go and Tekton
+// client satisfies it; clients that also implement
+// Watch(context.Context, metav1.ListOptions) (watch.Interface, error) are watched,
+// so the predicate is re-evaluated as soon as the resource changes.
+type ResourceClient[T runtime.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+}
+
+// watcher is implemented by ResourceClients that can watch.
+type watcher interface {
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// Unstructured adapts a dynamic client so WaitForResource works with any kind.
+//
+//	wait.WaitForResource(ctx, wait.Unstructured(c.Dynamic.Resource(gvr).Namespace(ns)), name, pred, "Ready")
+func Unstructured(ri dynamic.ResourceInterface) ResourceClient[*unstructured.Unstructured] {
+	return dynamicClient{ri: ri}
+}
+
+type dynamicClient struct {
+	ri dynamic.ResourceInterface
+}
+
+func (d dynamicClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*unstructured.Unstructured, error) {
+	return d.ri.Get(ctx, name, opts)
+}
+
+func (d dynamicClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return d.ri.Watch(ctx, opts)
+}
+
+// TimeoutError is returned when a resource did not reach the awaited state in time.
+// It carries the last state that was observed, so the failure is diagnosable
+// without re-running the spec.
+type TimeoutError struct {
+	Kind   string
+	Name   string
+	Desc   string
+	Waited time.Duration
+	// LastObserved is the last version of the resource seen, or nil if it was never found.
+	LastObserved runtime.Object
+	// LastErr is the last error returned while fetching the resource, e.g. NotFound.
+	LastErr error
+	// Cause is the context error that ended the wait.
+	Cause error
+	// Sections hold context gathered after the timeout, such as child TaskRun
+	// states and recent warning events.
+	Sections []Section
+}
+
+// Error renders the timeout and everything known about the resource as a tree:
+//
+//	timed out after 10m0s waiting for PipelineRun "build" (PipelineRunCompleted): context deadline exceeded
+//	├─ conditions
+//	│  └─ Succeeded=Unknown reason=Running: Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0
+//	├─ TaskRuns
+//	│  └─ build-compile (compile): Succeeded=Unknown reason=Pending: pod status "PodScheduled":"False"
+//	└─ warning events
+//	   └─ Pod/build-compile-pod FailedScheduling: 0/3 nodes are available
+func (e *TimeoutError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "timed out after %s waiting for %s %q (%s): %v",
+		e.Waited.Round(time.Second), e.Kind, e.Name, e.Desc, e.Cause)
+	var sections []Section
+	if e.LastErr != nil {
+		sections = append(sections, Section{Title: "last error: " + e.LastErr.Error()})
+	}
+	if e.LastObserved != nil {
+		if conditions := conditionLines(e.LastObserved); len(conditions) > 0 {
+			sections = append(sections, Section{Title: "conditions", Lines: conditions})
+		} else {
+			sections = append(sections, Section{Title: "last observed state", Lines: snapshot(e.LastObserved)})
+		}
+	}
+	renderTree(&sb, append(sections, e.Sections...))
+	return sb.String()
+}
+
+// Unwrap returns the context error, so errors.Is(err, context.DeadlineExceeded) holds.
+func (e *TimeoutError) Unwrap() error {
+	return e.Cause
+}
+
+// Option tunes a single WaitForResource call.
+type Option func(*options)
+
+type options struct {
+	allowNotFound bool
+	metric        string
+}
+
+// AllowNotFound keeps waiting while the resource does not exist yet, for waits on
+// resources a controller creates asynchronously. Without it NotFound is returned
+// immediately.
+func AllowNotFound() Option {
+	return func(o *options) { o.allowNotFound = true }
+}
+
+// Metric names the wait in the "Waiting:" log line, which defaults to
+// WaitFor<Kind>State.
+func Metric(name string) Option {
+	return func(o *options) { o.metric = name }
+}
+
+// WaitForResource waits until inState accepts the resource called name, watching it
+// when client supports watches and otherwise polling every config.APIRetry. The
+// resource is also re-read every config.APIRetry while watching, so a dropped
+// watch never stalls the wait. A read error, including NotFound unless
+// AllowNotFound is given, is returned immediately. On timeout the error is a
+// *TimeoutError holding the last observed state; otherwise the final observed
+// object is returned.
+func WaitForResource[T runtime.Object](ctx context.Context, client ResourceClient[T], name string, inState Predicate[T], desc string, opts ...Option) (T, error) { //nolint:revive
+	kind := kindOf[T]()
+	o := options{metric: "WaitFor" + kind + "State"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	log.Printf("Waiting: %s", fmt.Sprintf("%s/%s/%s", o.metric, name, desc))
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, config.APITimeout)
+	defer cancel()
+
+	var (
+		last     T
+		observed bool
+		lastErr  error
+		rewatch  bool
+	)
+	evaluate := func(obj T) (bool, error) {
+		last, observed, lastErr = obj, true, nil
+		return inState(obj)
+	}
+
+	events, stopWatch := watchResource(ctx, client, name)
+	defer func() { stopWatch() }()
+	ticker := time.NewTicker(config.APIRetry)
+	defer ticker.Stop()
+
+	for poll := true; ; {
+		if poll {
+			obj, err := client.Get(ctx, name, metav1.GetOptions{})
+			switch {
+			case err == nil:
+				if done, err := evaluate(obj); done || err != nil {
+					return obj, err
+				}
+			case apierrors.IsNotFound(err) && o.allowNotFound:
+				lastErr = err
+			case ctx.Err() == nil:
+				return last, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			timeout := &TimeoutError{Kind: kind, Name: name, Desc: desc, Waited: time.Since(start), LastErr: lastErr, Cause: ctx.Err()}
+			if observed {
+				timeout.LastObserved = last
+			}
+			return last, timeout
+		case <-ticker.C:
+			poll = true
+			if rewatch {
+				events, stopWatch = watchResource(ctx, client, name)
+				rewatch = false
+			}
+		case event, ok := <-events:
+			poll = false
+			if !ok {
+				// The watch expired or was closed by the server; poll until the
+				// next tick resumes it.
+				stopWatch()
+				events, rewatch = nil, true
+				continue
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				obj, ok := event.Object.(T)
+				if !ok || !hasName(obj, name) {
+					continue
+				}
+				if done, err := evaluate(obj); done || err != nil {
+					return obj, err
+				}
+			case watch.Deleted:
+				if hasName(event.Object, name) {
+					lastErr = fmt.Errorf("%s %q was deleted", kind, name)
+				}
+			default:
+				poll = true
+			}
+		}
+	}
+}
+
+// watchResource starts a watch on the named resource. It returns a nil channel,
+// which never fires, when the client cannot watch or the watch fails to start.
+func watchResource[T runtime.Object](ctx context.Context, client ResourceClient[T], name string) (<-chan watch.Event, func()) {
+	w, ok := client.(watcher)
+	if !ok {
+		return nil, func() {}
+	}
+	wi, err := w.Watch(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("watch on %q failed, falling back to polling: %v", name, err)
+		}
+		return nil, func() {}
+	}
+	return wi.ResultChan(), wi.Stop
+}
+
+// hasName reports whether obj is the named resource; fake clients and some
+// servers ignore field selectors.
+func hasName(obj runtime.Object, name string) bool {
+	accessor, ok := obj.(metav1.Object)
+	return ok && accessor.GetName() == name
+}
+
+// kindOf names the resource type T for log lines and errors.
+func kindOf[T runtime.Object]() string {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// snapshot renders the status of obj (or the whole object when it has none) as
+// YAML, keeping the first maxSnapshotLines lines.
+func snapshot(obj runtime.Object) []string {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return []string{fmt.Sprintf("[unable to render %T: %v]", obj, err)}
+	}
+	var view any = content
+	if status, ok := content["status"]; ok {
+		view = map[string]any{"status": status}
+	} else {
+		unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	}
+	data, err := yaml.Marshal(view)
+	if err != nil {
+		return []string{fmt.Sprintf("[unable to render %T: %v]", obj, err)}
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > maxSnapshotLines {
+		lines = append(lines[:maxSnapshotLines], fmt.Sprintf("... [%d more lines]", len(lines)-maxSnapshotLines))
+	}
+	return lines
+}
+
+// IsTimeout reports whether err is a *TimeoutError.
+func IsTimeout(err error) bool {
+	var timeout *TimeoutError
+	return errors.As(err, &timeout)
+}
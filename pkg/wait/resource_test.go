@@ -0,0 +1,121 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func podRunning(p *corev1.Pod) (bool, error) {
+	if p.Status.Phase == corev1.PodFailed {
+		return true, errors.New("pod failed")
+	}
+	return p.Status.Phase == corev1.PodRunning, nil
+}
+
+func pod(phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "demo"},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestWaitForResourceWakesOnWatchEvent(t *testing.T) {
+	pods := fake.NewClientset(pod(corev1.PodPending)).CoreV1().Pods("demo")
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		if _, err := pods.Update(context.Background(), pod(corev1.PodRunning), metav1.UpdateOptions{}); err != nil {
+			t.Errorf("update pod: %v", err)
+		}
+	}()
+
+	start := time.Now()
+	got, err := WaitForResource(context.Background(), pods, "build", podRunning, "PodRunning")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != corev1.PodRunning {
+		t.Fatalf("returned phase %q", got.Status.Phase)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("wait took %s; the watch event should have woken it before the next poll", elapsed)
+	}
+}
+
+func TestWaitForResourceStopsOnPredicateError(t *testing.T) {
+	pods := fake.NewClientset(pod(corev1.PodFailed)).CoreV1().Pods("demo")
+	if _, err := WaitForResource(context.Background(), pods, "build", podRunning, "PodRunning"); err == nil || err.Error() != "pod failed" {
+		t.Fatalf("WaitForResource() error = %v, want the predicate error", err)
+	}
+}
+
+// getOnly hides Watch so WaitForResource has to poll.
+type getOnly struct {
+	pods typedcorev1.PodInterface
+}
+
+func (g getOnly) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Pod, error) {
+	return g.pods.Get(ctx, name, opts)
+}
+
+func TestWaitForResourceTimeoutReportsLastObservedState(t *testing.T) {
+	pods := fake.NewClientset(pod(corev1.PodPending)).CoreV1().Pods("demo")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := WaitForResource[*corev1.Pod](ctx, getOnly{pods: pods}, "build", podRunning, "PodRunning")
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || !errors.Is(err, context.DeadlineExceeded) || !IsTimeout(err) {
+		t.Fatalf("WaitForResource() error = %v, want a *TimeoutError", err)
+	}
+	if timeout.Kind != "Pod" || timeout.LastObserved == nil {
+		t.Fatalf("unexpected timeout: %+v", timeout)
+	}
+	if msg := err.Error(); !strings.Contains(msg, `waiting for Pod "build" (PodRunning)`) || !strings.Contains(msg, "phase: Pending") {
+		t.Fatalf("error does not describe the last observed state:\n%s", msg)
+	}
+}
+
+func TestWaitForResourceFailsFastOnMissingResource(t *testing.T) {
+	pods := fake.NewClientset().CoreV1().Pods("demo")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := WaitForResource(ctx, pods, "build", podRunning, "PodRunning")
+	if !apierrors.IsNotFound(err) || IsTimeout(err) {
+		t.Fatalf("WaitForResource() error = %v, want NotFound without waiting", err)
+	}
+}
+
+func TestWaitForResourceAllowNotFound(t *testing.T) {
+	pods := fake.NewClientset().CoreV1().Pods("demo")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := WaitForResource(ctx, pods, "build", podRunning, "PodRunning", AllowNotFound())
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.LastObserved != nil || !apierrors.IsNotFound(timeout.LastErr) {
+		t.Fatalf("WaitForResource() error = %#v, want a timeout after NotFound", err)
+	}
+
+	pods = fake.NewClientset().CoreV1().Pods("demo")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		if _, err := pods.Create(context.Background(), pod(corev1.PodRunning), metav1.CreateOptions{}); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := WaitForResource(ctx, pods, "build", podRunning, "PodRunning", AllowNotFound()); err != nil {
+		t.Fatalf("WaitForResource() error = %v, want the Pod created later to be seen", err)
+	}
+}
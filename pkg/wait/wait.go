@@ -14,6 +14,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"knative.dev/pkg/apis"
 
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	triggersv1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
 )
@@ -32,88 +35,52 @@ func pollImmediateWithContext(ctx context.Context, fn func() (bool, error)) erro
 	})
 }
 
-// WaitForTaskRunState polls the status of the TaskRun called name from client every
-// interval until inState returns `true` indicating it is done, returns an
+// WaitForTaskRunState watches the TaskRun called name (see WaitForResource)
+// until inState returns `true` indicating it is done, returns an
 // error or timeout. desc will be used to name the metric that is emitted to
 // track how long it took for name to get into the state checked by inState.
 func WaitForTaskRunState(c *clients.Clients, name string, inState ConditionAccessorFn, desc string) error { //nolint:revive
-	metricName := fmt.Sprintf("WaitForTaskRunState/%s/%s", name, desc)
-	log.Printf("Waiting: %s", metricName)
-
-	return pollImmediateWithContext(c.Ctx, func() (bool, error) {
-		r, err := c.TaskRunClient.Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
+	_, err := WaitForResource(c.Ctx, c.TaskRunClient, name, func(r *pipelinev1.TaskRun) (bool, error) {
 		return inState(&r.Status)
-	})
+	}, desc)
+	return explainTaskRun(c, err)
 }
 
-// WaitForDeploymentState polls the status of the Deployment called name
-// from client every interval until inState returns `true` indicating it is done,
+// WaitForDeploymentState watches the Deployment called name (see WaitForResource)
+// until inState returns `true` indicating it is done,
 // returns an error or timeout. desc will be used to name the metric that is emitted to
 // track how long it took for name to get into the state checked by inState.
 func WaitForDeploymentState(c *clients.Clients, name string, namespace string, inState func(d *appsv1.Deployment) (bool, error), desc string) error { //nolint:revive
-	metricName := fmt.Sprintf("WaitForDeploymentState/%s/%s", name, desc)
-	log.Printf("Waiting: %s", metricName)
-
-	return pollImmediateWithContext(c.Ctx, func() (bool, error) {
-		d, err := c.KubeClient.Kube.AppsV1().Deployments(namespace).Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
-		return inState(d)
-	})
+	_, err := WaitForResource(c.Ctx, c.KubeClient.Kube.AppsV1().Deployments(namespace), name, inState, desc)
+	return err
 }
 
-// WaitForPodState polls the status of the Pod called name from client every
-// interval until inState returns `true` indicating it is done, returns an
+// WaitForPodState watches the Pod called name (see WaitForResource)
+// until inState returns `true` indicating it is done, returns an
 // error or timeout. desc will be used to name the metric that is emitted to
 // track how long it took for name to get into the state checked by inState.
 func WaitForPodState(c *clients.Clients, name string, namespace string, inState func(r *corev1.Pod) (bool, error), desc string) error { //nolint:revive
-	metricName := fmt.Sprintf("WaitForPodState/%s/%s", name, desc)
-	log.Printf("Waiting: %s", metricName)
-
-	return pollImmediateWithContext(c.Ctx, func() (bool, error) {
-		r, err := c.KubeClient.Kube.CoreV1().Pods(namespace).Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
-		return inState(r)
-	})
+	_, err := WaitForResource(c.Ctx, c.KubeClient.Kube.CoreV1().Pods(namespace), name, inState, desc)
+	return err
 }
 
-// WaitForPipelineRunState polls the status of the PipelineRun called name from client every
-// interval until inState returns `true` indicating it is done, returns an
+// WaitForPipelineRunState watches the PipelineRun called name (see WaitForResource)
+// until inState returns `true` indicating it is done, returns an
 // error or timeout. desc will be used to name the metric that is emitted to
 // track how long it took for name to get into the state checked by inState.
 func WaitForPipelineRunState(c *clients.Clients, name string, inState ConditionAccessorFn, desc string) error { //nolint:revive
-	metricName := fmt.Sprintf("WaitForPipelineRunState/%s/%s", name, desc)
-	log.Printf("Waiting: %s", metricName)
-
-	return pollImmediateWithContext(c.Ctx, func() (bool, error) {
-		r, err := c.PipelineRunClient.Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
+	_, err := WaitForResource(c.Ctx, c.PipelineRunClient, name, func(r *pipelinev1.PipelineRun) (bool, error) {
 		return inState(&r.Status)
-	})
+	}, desc)
+	return explainPipelineRun(c, err)
 }
 
-// WaitForServiceExternalIPState polls the status of a k8s Service called name from client every
-// interval until an external ip is assigned indicating it is done, returns an
+// WaitForServiceExternalIPState watches the k8s Service called name (see WaitForResource)
+// until an external ip is assigned indicating it is done, returns an
 // error or timeout.
 func WaitForServiceExternalIPState(c *clients.Clients, namespace, name string, inState func(s *corev1.Service) (bool, error), desc string) error { //nolint:revive
-	metricName := fmt.Sprintf("WaitForServiceExternalIPState/%s/%s", name, desc)
-	log.Printf("Waiting: %s", metricName)
-
-	return pollImmediateWithContext(c.Ctx, func() (bool, error) {
-		r, err := c.KubeClient.Kube.CoreV1().Services(namespace).Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
-		return inState(r)
-	})
+	_, err := WaitForResource(c.Ctx, c.KubeClient.Kube.CoreV1().Services(namespace), name, inState, desc, Metric("WaitForServiceExternalIPState"))
+	return err
 }
 
 // Succeed provides a poll condition function that checks if the ConditionAccessor
@@ -245,16 +212,38 @@ func WaitFor(ctx context.Context, waitFunc wait.ConditionFunc) error { //nolint:
 	return pollImmediateWithContext(ctx, waitFunc)
 }
 
+// WaitForEventListenerReady waits until every condition of the EventListener is true,
+// including the deployment available condition. It also waits while the
+// EventListener does not exist yet. On timeout the error shows the last conditions and the warning events of the EventListener and its deployment.
+func WaitForEventListenerReady(c *clients.Clients, namespace, name string) error { //nolint:revive
+	_, err := WaitForResource(c.Ctx, c.TriggersClient.TriggersV1alpha1().EventListeners(namespace), name,
+		func(el *triggersv1alpha1.EventListener) (bool, error) {
+			if el.Status.GetCondition(apis.ConditionType(appsv1.DeploymentAvailable)) == nil {
+				return false, nil
+			}
+			for _, cond := range el.Status.Conditions {
+				if cond.Status != corev1.ConditionTrue {
+					return false, nil
+				}
+			}
+			return true, nil
+		}, "EventListenerReady", AllowNotFound())
+	return explainEventListener(c, err)
+}
+
 // EventListenerReady returns a function that checks if all conditions on the
 // specified EventListener are true and that the deployment available condition
 // is within this set
 func EventListenerReady(c *clients.Clients, namespace, name string) wait.ConditionFunc {
 	return func() (bool, error) {
 		el, err := c.TriggersClient.TriggersV1alpha1().EventListeners(namespace).Get(c.Ctx, name, metav1.GetOptions{})
-		if err != nil && errors.IsNotFound(err) {
+		if errors.IsNotFound(err) {
 			log.Printf("EventListener not found")
 			return false, nil
 		}
+		if err != nil {
+			return false, err
+		}
 		log.Printf("EventListenerStatus: %+v", el.Status)
 		// No conditions have been set yet
 		if len(el.Status.Conditions) == 0 {
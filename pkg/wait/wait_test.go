@@ -0,0 +1,68 @@
+package wait
+
+import (
+	"errors"
+	"testing"
+
+	triggersv1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
+)
+
+func eventListener(conditions ...apis.Condition) *triggersv1alpha1.EventListener {
+	el := &triggersv1alpha1.EventListener{ObjectMeta: metav1.ObjectMeta{Name: "el", Namespace: "ci"}}
+	el.Status.Status = duckv1.Status{Conditions: conditions}
+	return el
+}
+
+func TestEventListenerReady(t *testing.T) {
+	available := apis.Condition{Type: apis.ConditionType(appsv1.DeploymentAvailable), Status: corev1.ConditionTrue}
+	ready := apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue}
+	notReady := apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionFalse}
+
+	tests := []struct {
+		name    string
+		c       *clients.FakeClients
+		getErr  error
+		want    bool
+		wantErr bool
+	}{{
+		name: "not found",
+		c:    clients.NewFakeClients(),
+	}, {
+		name: "no conditions",
+		c:    clients.NewFakeClients(eventListener()),
+	}, {
+		name: "no deployment condition",
+		c:    clients.NewFakeClients(eventListener(ready)),
+	}, {
+		name: "condition false",
+		c:    clients.NewFakeClients(eventListener(available, notReady)),
+	}, {
+		name: "ready",
+		c:    clients.NewFakeClients(eventListener(available, ready)),
+		want: true,
+	}, {
+		name:    "get error",
+		c:       clients.NewFakeClients(eventListener(available, ready)),
+		getErr:  errors.New("connection refused"),
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.getErr != nil {
+				tt.c.InjectError("get", "eventlisteners", tt.getErr)
+			}
+			got, err := EventListenerReady(tt.c.Clients, "ci", "el")()
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("EventListenerReady() = %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -27,7 +27,7 @@ set -euo pipefail
 #
 # Environment variables:
 #   CI=true             Automatically enables CI mode
-#   ARTIFACTS_DIR       Output directory for reports (default: ./artifacts)
+#   ARTIFACTS_DIR       Output directory for reports and failure bundles (default: ./artifacts)
 #   GINKGO_TIMEOUT      Test timeout (default: 4h)
 #   GINKGO_PROCS        Number of parallel processes (default: 4)
 #   UPGRADE_CHANNEL     Target channel for the upgrade mode (e.g. pipelines-1.18)
@@ -138,8 +138,11 @@ if [[ -n "$TEST_SUITE" ]]; then
     TEST_PATH="$TEST_SUITE"
 fi
 
-# Create artifacts directory
+# Create artifacts directory; export it as an absolute path so suites can write
+# failure bundles there (test binaries run from their package directories)
 mkdir -p "$ARTIFACTS"
+ARTIFACTS="$(cd "$ARTIFACTS" && pwd)"
+export ARTIFACTS_DIR="$ARTIFACTS"
 
 # Build ginkgo command
 GINKGO_ARGS=(
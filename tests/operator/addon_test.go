@@ -1,14 +1,13 @@
 package operator_test
 
 import (
-	"log"
-
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/cmd"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/operator"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 )
 
 var _ = Describe("Verify Addon E2E: PIPELINES-15", Serial, Ordered, ContinueOnFailure,
@@ -16,12 +15,7 @@ var _ = Describe("Verify Addon E2E: PIPELINES-15", Serial, Ordered, ContinueOnFa
 
 		BeforeAll(func() {
 			operator.ValidateOperatorInstallStatus(sharedClients, store.GetCRNames())
-
-			DeferCleanup(func() {
-				log.Println("Restoring addon config to defaults: resolverTasks=true, pipelineTemplates=true")
-				cmd.MustSucceed("oc", "patch", "TektonConfig", "config", "--type=merge", "-p",
-					`{"spec":{"addon":{"params":[{"name":"resolverTasks","value":"true"},{"name":"pipelineTemplates","value":"true"}]}}}`)
-			})
+			operator.GuardClusterState(sharedClients, store.GetCRNames())
 		})
 
 		It("Disable/Enable resolverTasks: PIPELINES-15-TC06", Label("sanity", "resolvertasks"), func() {
@@ -64,7 +58,7 @@ var _ = Describe("Verify Addon E2E: PIPELINES-15", Serial, Ordered, ContinueOnFa
 
 		It("Enable pipeline templates when clustertask is disabled: PIPELINES-15-TC05", Label("negative"), func() {
 			oc.UpdateAddonConfig("false", "true",
-				"pipelineTemplates cannot be true if resolverTask is false")
+				tektonconfig.PipelineTemplatesWithoutResolverMessage)
 		})
 
 		It("Verify versioned ecosystem tasks: PIPELINES-15-TC09", func() {
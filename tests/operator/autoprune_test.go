@@ -11,6 +11,7 @@ import (
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/operator"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 )
 
 var _ = Describe("Verify auto-prune E2E: PIPELINES-12", Serial,
@@ -19,6 +20,7 @@ var _ = Describe("Verify auto-prune E2E: PIPELINES-12", Serial,
 		BeforeEach(func() {
 			lastNamespace = store.Namespace()
 			operator.ValidateOperatorInstallStatus(sharedClients, store.GetCRNames())
+			operator.GuardClusterState(sharedClients, store.GetCRNames())
 		})
 
 		Context("Verify auto prune for taskrun: PIPELINES-12-TC01", Ordered, ContinueOnFailure, Label("sanity"), func() {
@@ -309,17 +311,17 @@ var _ = Describe("Verify auto-prune E2E: PIPELINES-12", Serial,
 
 				// Test 1: Both keep and keep-since specified
 				stderr1 := oc.UpdatePrunerConfigExpectError("2", "*/8 * * * *", "pipelinerun,taskrun", "2", true, true)
-				Expect(stderr1).To(ContainSubstring("expected exactly one, got both: spec.pruner.keep, spec.pruner.keep-since"),
+				Expect(stderr1).To(ContainSubstring(tektonconfig.KeepAndKeepSinceMessage),
 					"expected validation error for both keep and keep-since")
 
 				// Test 2: Invalid resource type "taskrunas"
 				stderr2 := oc.UpdatePrunerConfigExpectError("2", "*/8 * * * *", "pipelinerun,taskrunas", "", true, false)
-				Expect(stderr2).To(ContainSubstring("invalid value: taskrunas"),
+				Expect(stderr2).To(ContainSubstring(tektonconfig.InvalidValueMessage("taskrunas")),
 					"expected validation error for invalid resource taskrunas")
 
 				// Test 3: Invalid resource type "pipelinerunas"
 				stderr3 := oc.UpdatePrunerConfigExpectError("2", "*/8 * * * *", "pipelinerunas,taskrun", "", true, false)
-				Expect(stderr3).To(ContainSubstring("invalid value: pipelinerunas"),
+				Expect(stderr3).To(ContainSubstring(tektonconfig.InvalidValueMessage("pipelinerunas")),
 					"expected validation error for invalid resource pipelinerunas")
 			})
 		})
@@ -39,25 +39,22 @@ var _ = Describe("Olm Openshift Pipelines operator post upgrade tests: PIPELINES
 
 			// GitHub push event
 			route1 := triggers.GetRoute("listener-ctb-github-push", ns)
-			resp1, payload1 := triggers.MockPostEvent(route1, "github", "push",
+			resp1, _ := triggers.MockPostEvent(route1, "github", "push",
 				"testdata/triggers/github-ctb/push.json", false)
-			store.PutScenarioData("payload", string(payload1))
 			triggers.AssertElResponse(sharedClients, resp1, "listener-ctb-github-push", ns)
 			pipelines.ValidatePipelineRun(sharedClients, "pipelinerun-git-push-ctb", "successful", ns)
 
 			// GitHub PR event via triggersCRD
 			route2 := triggers.GetRoute("listener-triggerref", ns)
-			resp2, payload2 := triggers.MockPostEvent(route2, "github", "pull_request",
+			resp2, _ := triggers.MockPostEvent(route2, "github", "pull_request",
 				"testdata/triggers/triggersCRD/pull-request.json", false)
-			store.PutScenarioData("payload", string(payload2))
 			triggers.AssertElResponse(sharedClients, resp2, "listener-triggerref", ns)
 			pipelines.ValidatePipelineRun(sharedClients, "parallel-pipelinerun", "successful", ns)
 
 			// Bitbucket event
 			route3 := triggers.GetRoute("bitbucket-listener", ns)
-			resp3, payload3 := triggers.MockPostEvent(route3, "bitbucket", "refs_changed",
+			resp3, _ := triggers.MockPostEvent(route3, "bitbucket", "refs_changed",
 				"testdata/triggers/bitbucket/refs-change-event.json", false)
-			store.PutScenarioData("payload", string(payload3))
 			triggers.AssertElResponse(sharedClients, resp3, "bitbucket-listener", ns)
 			pipelines.ValidateTaskRun(sharedClients, "bitbucket-run", "Failure", ns)
 		})
@@ -69,9 +66,8 @@ var _ = Describe("Olm Openshift Pipelines operator post upgrade tests: PIPELINES
 			cmd.MustSucceed("oc", "project", ns)
 
 			route := triggers.GetRoute("listener-embed-binding", ns)
-			resp, payload := triggers.MockPostEvent(route, "github", "push",
+			resp, _ := triggers.MockPostEvent(route, "github", "push",
 				"testdata/push.json", true)
-			store.PutScenarioData("payload", string(payload))
 			triggers.AssertElResponse(sharedClients, resp, "listener-embed-binding", ns)
 			pipelines.ValidatePipelineRun(sharedClients, "simple-pipeline-run", "successful", ns)
 		})
@@ -7,7 +7,6 @@ import (
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/openshift"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/operator"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/pipelines"
-	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/triggers"
 )
 
@@ -42,9 +41,8 @@ var _ = Describe("Openshift Pipelines pre upgrade specs: PIPELINES-18", Serial,
 
 			// Expose eventlistener and send mock event
 			route := triggers.ExposeEventListener(sharedClients, "listener-ctb-github-push", ns)
-			resp, payload := triggers.MockPostEvent(route, "github", "push",
+			resp, _ := triggers.MockPostEvent(route, "github", "push",
 				"testdata/triggers/github-ctb/push.json", false)
-			store.PutScenarioData("payload", string(payload))
 			triggers.AssertElResponse(sharedClients, resp, "listener-ctb-github-push", ns)
 
 			// Verify pipelinerun
@@ -60,9 +58,8 @@ var _ = Describe("Openshift Pipelines pre upgrade specs: PIPELINES-18", Serial,
 
 			// Expose eventlistener and send mock PR event
 			route2 := triggers.ExposeEventListener(sharedClients, "listener-triggerref", ns)
-			resp2, payload2 := triggers.MockPostEvent(route2, "github", "pull_request",
+			resp2, _ := triggers.MockPostEvent(route2, "github", "pull_request",
 				"testdata/triggers/triggersCRD/pull-request.json", false)
-			store.PutScenarioData("payload", string(payload2))
 			triggers.AssertElResponse(sharedClients, resp2, "listener-triggerref", ns)
 
 			pipelines.ValidatePipelineRun(sharedClients, "parallel-pipelinerun", "successful", ns)
@@ -74,9 +71,8 @@ var _ = Describe("Openshift Pipelines pre upgrade specs: PIPELINES-18", Serial,
 			oc.LinkSecretToSA("bitbucket-secret", "pipeline", ns)
 
 			route3 := triggers.ExposeEventListener(sharedClients, "bitbucket-listener", ns)
-			resp3, payload3 := triggers.MockPostEvent(route3, "bitbucket", "refs_changed",
+			resp3, _ := triggers.MockPostEvent(route3, "bitbucket", "refs_changed",
 				"testdata/triggers/bitbucket/refs-change-event.json", false)
-			store.PutScenarioData("payload", string(payload3))
 			triggers.AssertElResponse(sharedClients, resp3, "bitbucket-listener", ns)
 
 			pipelines.ValidateTaskRun(sharedClients, "bitbucket-run", "Failure", ns)
@@ -97,9 +93,8 @@ var _ = Describe("Openshift Pipelines pre upgrade specs: PIPELINES-18", Serial,
 			oc.Create("testdata/triggers/eventlisteners/eventlistener-embeded-binding.yaml", ns)
 
 			route := triggers.ExposeEventListenerForTLS(sharedClients, "listener-embed-binding", ns)
-			resp, payload := triggers.MockPostEvent(route, "github", "push",
+			resp, _ := triggers.MockPostEvent(route, "github", "push",
 				"testdata/push.json", true)
-			store.PutScenarioData("payload", string(payload))
 			triggers.AssertElResponse(sharedClients, resp, "listener-embed-binding", ns)
 
 			pipelines.ValidatePipelineRun(sharedClients, "simple-pipeline-run", "successful", ns)
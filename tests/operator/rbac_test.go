@@ -15,11 +15,7 @@ var _ = Describe("Verify RBAC Resources and CA Bundle Configuration", Serial, Or
 		BeforeAll(func() {
 			lastNamespace = config.TargetNamespace
 			operator.ValidateOperatorInstallStatus(sharedClients, store.GetCRNames())
-
-			DeferCleanup(func() {
-				oc.UpdateTektonConfigParam("createRbacResource", "true")
-				oc.UpdateTektonConfigParam("createCABundleConfigMaps", "true")
-			})
+			operator.GuardClusterState(sharedClients, store.GetCRNames())
 		})
 
 		// PIPELINES-11-TC01
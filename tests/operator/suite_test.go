@@ -1,7 +1,6 @@
 package operator_test
 
 import (
-	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
@@ -10,6 +9,7 @@ import (
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/diagnostics"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/hooks"
 	occmd "github.com/openshift-pipelines/release-tests-ginkgo/pkg/oc"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
@@ -39,7 +39,7 @@ type clientConfig struct {
 }
 
 var _ = SynchronizedBeforeSuite(
-	// Node 1 only: validate cluster connectivity and serialize config
+	// Node 1 only: validate cluster connectivity and serialize config with shared store keys
 	func() []byte {
 		// Verify cluster is reachable by creating clients
 		cs, err := clients.NewClientsWithContext(
@@ -51,28 +51,28 @@ var _ = SynchronizedBeforeSuite(
 		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes clients on node 1")
 		_ = cs // validation only on node 1
 
+		// Seed store so that store.GetCRNames() returns the right names for all
+		// operator tests on every node. The TektonConfig CR is always named "config".
+		store.SetCRNames(utils.ResourceNames{TektonConfig: "config"})
+
 		cfg := clientConfig{
 			Kubeconfig:      config.Flags.Kubeconfig,
 			Cluster:         config.Flags.Cluster,
 			Context:         config.Flags.Context,
 			TargetNamespace: config.TargetNamespace,
 		}
-		data, err := json.Marshal(cfg)
+		data, err := store.EncodeSuiteData(cfg)
 		Expect(err).NotTo(HaveOccurred(), "Failed to serialize client config")
 		return data
 	},
-	// All nodes: deserialize config, create node-local clients, and seed the store.
+	// All nodes: deserialize config and shared store keys, and create node-local clients.
 	func(data []byte) {
 		var cfg clientConfig
-		Expect(json.Unmarshal(data, &cfg)).To(Succeed(), "Failed to deserialize client config")
+		Expect(store.DecodeSuiteData(data, &cfg)).To(Succeed(), "Failed to deserialize client config")
 
 		var err error
 		sharedClients, err = clients.NewClientsWithContext(cfg.Kubeconfig, cfg.Cluster, cfg.Context, cfg.TargetNamespace)
 		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes clients")
-
-		// Seed store so that store.GetCRNames() returns the right names for all
-		// operator tests. The TektonConfig CR is always named "config".
-		store.SetCRNames(utils.ResourceNames{TektonConfig: "config"})
 	},
 )
 
@@ -83,3 +83,12 @@ var _ = AfterSuite(func() {
 
 // Automatically create namespace per Describe block
 var _ = hooks.AutoNamespacePerDescribe(&lastNamespace, func() *clients.Clients { return sharedClients })
+
+// Save a failure bundle under ARTIFACTS_DIR for triage without cluster access.
+var _ = ReportAfterEach(diagnostics.WriteBundleOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+
+// Scope typed store keys to specs and containers.
+var _ = hooks.ScopedStore()
+
+// Attach the commands each spec ran to its report.
+var _ = hooks.AuditCommands()
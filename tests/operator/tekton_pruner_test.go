@@ -12,28 +12,21 @@ import (
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/operator"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/pipelines"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/tektonconfig"
 )
 
 // Each inner Describe gets its own namespace via hooks.AutoNamespacePerDescribe
 // (registered in suite_test.go). The outer Ordered+Serial container guarantees
 // TC-01 through TC-11 execute sequentially, matching the Gauge spec order.
-// Tekton-pruner is enabled by TC-01 and stays enabled through TC-11; the AfterAll
-// restores the cluster to its pre-test state (legacy pruner re-enabled).
+// Tekton-pruner is enabled by TC-01 and stays enabled through TC-11; the guard
+// registered in BeforeAll restores TektonConfig to its pre-test state.
 var _ = Describe("Verify Tekton Pruner Functionality: PIPELINES-36", Serial, Ordered, ContinueOnFailure,
 	Label("e2e", "integration", "operator", "admin", "tekton-pruner", "pruner"), func() {
 
 		// Matches Gauge's spec-level Pre condition — runs once before all TCs.
 		BeforeAll(func() {
 			operator.ValidateOperatorInstallStatus(sharedClients, store.GetCRNames())
-		})
-
-		AfterAll(func() {
-			oc.SetTektonPrunerGlobalConfig("enforcedConfigLevel", "global", "")
-			oc.SetTektonPrunerGlobalConfig("ttlSecondsAfterFinished", "null", "")
-			oc.SetTektonPrunerGlobalConfig("successfulHistoryLimit", "null", "")
-			oc.SetTektonPrunerGlobalConfig("failedHistoryLimit", "null", "")
-			oc.DisableTektonPruner()
-			oc.EnableLegacyPruner()
+			operator.GuardClusterState(sharedClients, store.GetCRNames())
 		})
 
 		// PIPELINES-36-TC-01
@@ -51,11 +44,11 @@ var _ = Describe("Verify Tekton Pruner Functionality: PIPELINES-36", Serial, Ord
 		Describe("Webhook: Negative Values & Invalid Type: PIPELINES-36-TC02", func() {
 			It("should reject invalid tekton-pruner global-config values via webhook", func() {
 				oc.SetTektonPrunerGlobalConfig("ttlSecondsAfterFinished", "-1",
-					"ttlSecondsAfterFinished cannot be negative")
+					tektonconfig.NegativeTTLMessage)
 				oc.SetTektonPrunerGlobalConfig("ttlSecondsAfterFinished", "60s",
-					"cannot unmarshal string")
+					tektonconfig.WrongTypeMessage)
 				oc.SetTektonPrunerGlobalConfig("successfulHistoryLimit", "not-a-number",
-					"cannot unmarshal string")
+					tektonconfig.WrongTypeMessage)
 			})
 		})
 
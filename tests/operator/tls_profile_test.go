@@ -37,8 +37,6 @@ var tlsEnvDeployments = []string{
 var _ = Describe("SRVKP-11926: Central TLS profile propagation to Pipelines components",
 	Serial, Ordered, ContinueOnFailure, Label("e2e", "operator", "admin", "tls-profile"), func() {
 
-		var originalProfile string
-
 		BeforeAll(func() {
 			if operator.IsHostedCluster(sharedClients) {
 				Skip("Skipping TLS profile propagation tests: APIServer/cluster is immutable on HyperShift hosted clusters")
@@ -48,15 +46,8 @@ var _ = Describe("SRVKP-11926: Central TLS profile propagation to Pipelines comp
 			operator.EnsureTektonConfigStatusInstalled(
 				sharedClients.TektonConfig(), store.GetCRNames())
 
-			originalProfile = operator.GetClusterTLSProfileType(sharedClients)
-			log.Printf("Saved original cluster TLS profile: %q", originalProfile)
-
-			DeferCleanup(func() {
-				log.Printf("Restoring cluster TLS profile to %q", originalProfile)
-				operator.PatchClusterTLSProfile(sharedClients, originalProfile)
-				operator.EnsureTektonConfigStatusInstalled(
-					sharedClients.TektonConfig(), store.GetCRNames())
-			})
+			// Restores the original cluster TLS profile and waits for TektonConfig.
+			operator.GuardClusterState(sharedClients, store.GetCRNames())
 		})
 
 		// ── Intermediate profile ──────────────────────────────────────────────
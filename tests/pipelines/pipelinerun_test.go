@@ -21,9 +21,9 @@ var _ = Describe("Run sample pipeline: PIPELINES-03-TC01", Label("e2e", "pipelin
 })
 
 var _ = Describe("Pipelinerun Timeout failure: PIPELINES-03-TC04", Label("e2e", "pipelines", "non-admin", "sanity"), func() {
-	It("should fail with timeout", SpecTimeout(10*time.Minute), func(_ SpecContext) {
+	It("should fail with timeout", SpecTimeout(10*time.Minute), func(ctx SpecContext) {
 		ns := lastNamespace
-		oc.Create("testdata/v1beta1/pipelinerun/pipelineruntimeout.yaml", ns)
+		oc.WithContext(ctx).Create("testdata/v1beta1/pipelinerun/pipelineruntimeout.yaml", ns)
 		pipelines.ValidatePipelineRun(sharedClients, "pear", "timeout", ns)
 	})
 })
@@ -37,10 +37,11 @@ var _ = Describe("Configure execution results at Task level: PIPELINES-03-TC05",
 })
 
 var _ = Describe("Cancel pipelinerun: PIPELINES-03-TC06", Label("e2e", "pipelines", "integration", "non-admin", "sanity"), func() {
-	It("should cancel a running pipelinerun", SpecTimeout(10*time.Minute), func(_ SpecContext) {
+	It("should cancel a running pipelinerun", SpecTimeout(10*time.Minute), func(ctx SpecContext) {
 		ns := lastNamespace
-		oc.Create("testdata/pvc/pvc.yaml", ns)
-		oc.Create("testdata/v1beta1/pipelinerun/pipelinerun.yaml", ns)
+		ocCtx := oc.WithContext(ctx)
+		ocCtx.Create("testdata/pvc/pvc.yaml", ns)
+		ocCtx.Create("testdata/v1beta1/pipelinerun/pipelinerun.yaml", ns)
 		pipelines.ValidatePipelineRun(sharedClients, "output-pipeline-run-v1b1", "canceled", ns)
 	})
 })
@@ -1,7 +1,6 @@
 package tektonkueue_test
 
 import (
-	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
@@ -9,25 +8,12 @@ import (
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/diagnostics"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/hooks"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
 )
 
-var (
-	sharedClients *clients.Clients
-	hubConfig     clientConfig
-	spokeConfigs  []clientConfig
-)
-
-type clientConfig struct {
-	Kubeconfig      string `json:"kubeconfig"`
-	Cluster         string `json:"cluster,omitempty"`
-	Context         string `json:"context,omitempty"`
-	TargetNamespace string `json:"targetNamespace"`
-}
-
-type suiteConfig struct {
-	Hub    clientConfig   `json:"hub"`
-	Spokes []clientConfig `json:"spokes"`
-}
+var sharedClients *clients.Clients
 
 func TestTektonKueue(t *testing.T) {
 	config.MustLoadEnvironment()
@@ -38,46 +24,44 @@ func TestTektonKueue(t *testing.T) {
 var _ = SynchronizedBeforeSuite(
 	func() []byte {
 		Expect(config.Flags.SpokeKubeconfigs).NotTo(BeEmpty(), "at least one --spoke-kubeconfig is required")
-		Expect(len(config.Flags.SpokeContexts) == 0 || len(config.Flags.SpokeContexts) == len(config.Flags.SpokeKubeconfigs)).To(BeTrue(),
-			"provide either no --spoke-context flags or one per --spoke-kubeconfig")
-
-		hub := clientConfig{
+		hub := clients.ClusterConfig{
 			Kubeconfig:      config.Flags.Kubeconfig,
 			Cluster:         config.Flags.Cluster,
 			Context:         config.Flags.Context,
 			TargetNamespace: config.TargetNamespace,
 		}
-		var err error
-		sharedClients, err = clients.NewClientsWithContext(hub.Kubeconfig, hub.Cluster, hub.Context, hub.TargetNamespace)
-		Expect(err).NotTo(HaveOccurred(), "failed to create hub clients")
+		configs, err := clients.HubAndSpokes(hub, config.Flags.SpokeKubeconfigs, config.Flags.SpokeContexts)
+		Expect(err).NotTo(HaveOccurred())
 
-		spokes := make([]clientConfig, len(config.Flags.SpokeKubeconfigs))
-		for i, kubeconfig := range config.Flags.SpokeKubeconfigs {
-			contextName := ""
-			if len(config.Flags.SpokeContexts) != 0 {
-				contextName = config.Flags.SpokeContexts[i]
-			}
-			spokes[i] = clientConfig{Kubeconfig: kubeconfig, Context: contextName, TargetNamespace: config.TargetNamespace}
-			_, err := clients.BuildClientConfigWithContext(kubeconfig, "", contextName)
-			Expect(err).NotTo(HaveOccurred(), "failed to load spoke-%d kubeconfig", i+1)
+		for _, cfg := range configs[1:] {
+			_, err := clients.BuildClientConfigWithContext(cfg.Kubeconfig, "", cfg.Context)
+			Expect(err).NotTo(HaveOccurred(), "failed to load %s kubeconfig", cfg.Name)
 		}
 
-		data, err := json.Marshal(suiteConfig{Hub: hub, Spokes: spokes})
+		data, err := store.EncodeSuiteData(configs)
 		Expect(err).NotTo(HaveOccurred(), "failed to serialize cluster configuration")
 		return data
 	},
 	func(data []byte) {
-		var cfg suiteConfig
-		Expect(json.Unmarshal(data, &cfg)).To(Succeed(), "failed to deserialize cluster configuration")
+		var configs []clients.ClusterConfig
+		Expect(store.DecodeSuiteData(data, &configs)).To(Succeed(), "failed to deserialize cluster configuration")
 
-		var err error
-		sharedClients, err = clients.NewClientsWithContext(cfg.Hub.Kubeconfig, cfg.Hub.Cluster, cfg.Hub.Context, cfg.Hub.TargetNamespace)
-		Expect(err).NotTo(HaveOccurred(), "failed to create hub clients")
-		hubConfig = cfg.Hub
-		spokeConfigs = cfg.Spokes
+		clusters, err := clients.NewClusterSet(configs...)
+		Expect(err).NotTo(HaveOccurred())
+		store.Clusters.Set(clusters)
+		sharedClients = clusters.Hub().Clients
 	},
 )
 
 var _ = AfterSuite(func() {
 	_ = config.RemoveTempDir()
 })
+
+// Save a failure bundle under ARTIFACTS_DIR for triage without cluster access.
+var _ = ReportAfterEach(diagnostics.WriteBundleOnFailure(nil, func() *clients.Clients { return sharedClients }))
+
+// Scope typed store keys to specs and containers.
+var _ = hooks.ScopedStore()
+
+// Attach the commands each spec ran to its report.
+var _ = hooks.AuditCommands()
@@ -1,34 +1,33 @@
 package tektonkueue_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
 	. "github.com/onsi/gomega"    //nolint:revive,staticcheck // dot import is idiomatic for Gomega
 
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	olmpkg "github.com/openshift-pipelines/release-tests-ginkgo/pkg/olm"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
 )
 
 var _ = Describe("Multi-cluster operator bootstrap", Serial, Label("tekton-kueue", "install", "admin"), func() {
 	It("installs the required operators on the hub and every spoke", NodeTimeout(6*time.Hour), func(specCtx SpecContext) {
-		type clusterTarget struct {
-			name   string
-			config clientConfig
-		}
-		clusters := make([]clusterTarget, 0, 1+len(spokeConfigs))
-		clusters = append(clusters, clusterTarget{name: "hub", config: hubConfig})
-		for i, cfg := range spokeConfigs {
-			clusters = append(clusters, clusterTarget{name: fmt.Sprintf("spoke-%d", i+1), config: cfg})
-		}
-
-		for _, cluster := range clusters {
-			By(fmt.Sprintf("bootstrapping %s", cluster.name))
-			controllerClient, err := sharedClients.NewClientFromKubeconfig(cluster.config.Kubeconfig, cluster.config.Cluster, cluster.config.Context)
-			Expect(err).NotTo(HaveOccurred(), "failed to create %s client", cluster.name)
-
+		results := store.Clusters.Get().ForEach(specCtx, func(ctx context.Context, cluster *clients.Cluster) error {
+			controllerClient, err := cluster.NewControllerClient()
+			if err != nil {
+				return err
+			}
 			bootstrap := olmpkg.ClusterBootstrap{Client: controllerClient}
-			Expect(bootstrap.EnsureOperators(specCtx)).To(Succeed(), "failed to bootstrap %s", cluster.name)
+			return bootstrap.EnsureOperators(ctx)
+		})
+		for _, result := range results {
+			if result.Err == nil {
+				By(fmt.Sprintf("bootstrapped %s", result.Cluster))
+			}
 		}
+		Expect(results.Err()).NotTo(HaveOccurred(), "failed to bootstrap %d of %d clusters", len(results.Failed()), len(results))
 	})
 })
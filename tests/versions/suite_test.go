@@ -1,7 +1,6 @@
 package versions_test
 
 import (
-	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive,staticcheck // dot import is idiomatic for Ginkgo
@@ -9,7 +8,9 @@ import (
 
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/clients"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/config"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/diagnostics"
 	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/hooks"
+	"github.com/openshift-pipelines/release-tests-ginkgo/pkg/store"
 )
 
 var sharedClients *clients.Clients
@@ -19,7 +20,7 @@ var sharedClients *clients.Clients
 var lastNamespace string
 
 func TestVersions(t *testing.T) {
-	config.MustLoadEnvironment()
+	config.MustLoadEnvironment("versions")
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Versions Suite", Label("versions"))
 }
@@ -51,14 +52,14 @@ var _ = SynchronizedBeforeSuite(
 			Context:         config.Flags.Context,
 			TargetNamespace: config.TargetNamespace,
 		}
-		data, err := json.Marshal(cfg)
+		data, err := store.EncodeSuiteData(cfg)
 		Expect(err).NotTo(HaveOccurred(), "Failed to serialize client config")
 		return data
 	},
 	// All nodes: deserialize config and create node-local clients
 	func(data []byte) {
 		var cfg clientConfig
-		Expect(json.Unmarshal(data, &cfg)).To(Succeed(), "Failed to deserialize client config")
+		Expect(store.DecodeSuiteData(data, &cfg)).To(Succeed(), "Failed to deserialize client config")
 
 		var err error
 		sharedClients, err = clients.NewClientsWithContext(cfg.Kubeconfig, cfg.Cluster, cfg.Context, cfg.TargetNamespace)
@@ -76,4 +77,11 @@ var _ = hooks.AutoNamespacePerDescribe(&lastNamespace, func() *clients.Clients {
 
 // Collect diagnostics (pod logs, events, resource state) on test failure.
 // Disabled for cleaner console output
-// var _ = ReportAfterEach(diagnostics.CollectOnFailure(&lastNamespace))
+// var _ = ReportAfterEach(diagnostics.CollectOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+
+// Save a failure bundle under ARTIFACTS_DIR for triage without cluster access.
+var _ = ReportAfterEach(diagnostics.WriteBundleOnFailure(&lastNamespace, func() *clients.Clients { return sharedClients }))
+
+// Report operator state the suite left modified, scope typed store keys and
+// attach the commands each spec ran to its report.
+var _ = hooks.SuiteHooks()